@@ -0,0 +1,145 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+
+	"interactive-feedback-mcp/internal/attachments"
+	"interactive-feedback-mcp/internal/tools"
+	"interactive-feedback-mcp/internal/types"
+)
+
+// attachmentsFileName is where the GUI lists the files the user attached.
+// Pasted images are saved next to it.
+const attachmentsFileName = "attachments.json"
+
+// promptAttachmentsDir holds the agent's attachments for the GUI to show
+const promptAttachmentsDir = "prompt"
+
+// shownAttachment is an agent attachment as handed to the GUI. Images are
+// written to files; text is passed inline.
+type shownAttachment struct {
+	Type     string `json:"type"` // image, text or file
+	Name     string `json:"name"`
+	MimeType string `json:"mime_type"`
+	Size     int64  `json:"size"`
+	Path     string `json:"path,omitempty"`
+	Text     string `json:"text,omitempty"`
+	Language string `json:"language,omitempty"`
+}
+
+// promptAttachments loads the attachments argument of interactive_feedback
+func promptAttachments(projectDir string, argument interface{}) ([]*attachments.File, error) {
+	if argument == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(argument)
+	if err != nil {
+		return nil, fmt.Errorf("invalid attachments: %w", err)
+	}
+	var items []attachments.PromptItem
+	if err := json.Unmarshal(data, &items); err != nil {
+		return nil, fmt.Errorf("invalid attachments: %w", err)
+	}
+	return attachments.ResolvePrompt(projectDir, items, attachments.DefaultLimits)
+}
+
+// writePromptAttachments writes the agent's attachments into dir for the GUI
+// and returns the path of the file listing them
+func writePromptAttachments(dir string, files []*attachments.File) (string, error) {
+	promptDir := filepath.Join(dir, promptAttachmentsDir)
+	if err := os.Mkdir(promptDir, 0700); err != nil {
+		return "", fmt.Errorf("failed to write attachments for the GUI: %w", err)
+	}
+
+	shown := make([]shownAttachment, len(files))
+	for i, file := range files {
+		shown[i] = shownAttachment{
+			Type:     "text",
+			Name:     file.Name,
+			MimeType: file.MimeType,
+			Size:     file.Size,
+			Language: file.Language,
+		}
+		switch {
+		case file.IsImage():
+			shown[i].Type = "image"
+			shown[i].Path = filepath.Join(promptDir, fmt.Sprintf("%d-%s", i+1, filepath.Base(file.Name)))
+			if err := os.WriteFile(shown[i].Path, file.Data, 0600); err != nil {
+				return "", fmt.Errorf("failed to write attachments for the GUI: %w", err)
+			}
+		case file.IsText():
+			shown[i].Text = string(file.Data)
+		default:
+			shown[i].Type = "file" // binary files are only listed
+		}
+	}
+
+	listPath := filepath.Join(promptDir, attachmentsFileName)
+	data, err := json.Marshal(shown)
+	if err == nil {
+		err = os.WriteFile(listPath, data, 0600)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to write attachments for the GUI: %w", err)
+	}
+	return listPath, nil
+}
+
+// readAttachments loads the files the GUI listed in dir. Files saved in dir
+// itself (pasted images) lose their path, as dir is removed afterwards.
+func readAttachments(dir string) ([]*attachments.File, []string) {
+	data, err := os.ReadFile(filepath.Join(dir, attachmentsFileName))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, []string{fmt.Sprintf("failed to read attachments: %v", err)}
+	}
+
+	var paths []string
+	if err := json.Unmarshal(data, &paths); err != nil {
+		return nil, []string{fmt.Sprintf("failed to read attachments: %v", err)}
+	}
+
+	files, errs := attachments.LoadAll(paths, attachments.DefaultLimits)
+	for _, file := range files {
+		if filepath.Dir(file.Path) == dir {
+			file.Path = ""
+		}
+	}
+	messages := make([]string, len(errs))
+	for i, err := range errs {
+		messages[i] = err.Error()
+	}
+	return files, messages
+}
+
+// attachmentContent returns a file as an MCP content block: images as image
+// blocks, everything else as an embedded resource
+func attachmentContent(file *attachments.File) map[string]interface{} {
+	if file.IsImage() {
+		return tools.ImageContent(file.Data, file.MimeType)
+	}
+	uri := "attachment:" + url.PathEscape(file.Name)
+	if file.Path != "" {
+		uri = (&url.URL{Scheme: "file", Path: filepath.ToSlash(file.Path)}).String()
+	}
+	if file.IsText() {
+		return tools.TextResourceContent(uri, file.MimeType, string(file.Data))
+	}
+	return tools.BlobResourceContent(uri, file.MimeType, file.Data)
+}
+
+// attachmentSummary lists attachments for results and history entries
+func attachmentSummary(files []*attachments.File) []types.Attachment {
+	summary := make([]types.Attachment, len(files))
+	for i, file := range files {
+		summary[i] = file.Attachment
+	}
+	return summary
+}
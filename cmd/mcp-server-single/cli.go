@@ -0,0 +1,500 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"interactive-feedback-mcp/internal/config"
+	"interactive-feedback-mcp/internal/export"
+	"interactive-feedback-mcp/internal/gitignore"
+	"interactive-feedback-mcp/internal/types"
+	"interactive-feedback-mcp/internal/workspace"
+)
+
+const cliUsage = `Usage: mcp-server-single [command]
+
+Without a command the binary runs as an MCP server on stdin/stdout.
+
+Commands:
+  config locate [projectDirectory]   Show where configuration and project state are stored
+  config get [--user] [--project dir] [key]
+                                     Print a project (or user) setting, or all of them
+  config set [--user] [--project dir] key value
+                                     Change a setting; the value is JSON or a plain string
+  history show [projectDirectory]    Print the project's conversation history
+  history export [--format markdown|json|text] [--output file] [projectDirectory]
+                                     Export the project's conversation history
+  history clear [projectDirectory]   Delete the project's conversation history
+  gitignore install [projectDirectory]
+                                     Keep the project config file out of git
+  gitignore remove [projectDirectory]
+                                     Undo gitignore install
+  doctor [projectDirectory]          Check the GUI's dependencies and the project's setup
+  help                               Show this help
+`
+
+// runCLI handles the management subcommands and returns the process exit code
+func runCLI(args []string) int {
+	switch args[0] {
+	case "help", "-h", "--help":
+		fmt.Print(cliUsage)
+		return 0
+	case "config":
+		return runConfigCommand(args[1:], os.Stdout)
+	case "history":
+		return runHistoryCommand(args[1:], os.Stdout)
+	case "gitignore":
+		return runGitignoreCommand(args[1:], os.Stdout)
+	case "doctor":
+		return runDoctor(args[1:], os.Stdout)
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown command: %s\n\n%s", args[0], cliUsage)
+		return 2
+	}
+}
+
+func runConfigCommand(args []string, out io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprint(os.Stderr, cliUsage)
+		return 2
+	}
+
+	switch args[0] {
+	case "locate":
+		return runConfigLocate(args[1:], out)
+	case "get":
+		return runConfigGet(args[1:], out)
+	case "set":
+		return runConfigSet(args[1:], out)
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown config command: %s\n\n%s", args[0], cliUsage)
+		return 2
+	}
+}
+
+func runConfigLocate(args []string, out io.Writer) int {
+	configManager, err := config.NewConfigManager()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error creating config manager: %v\n", err)
+		return 1
+	}
+
+	projectDir, err := cliProjectDir(args)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	projectConfigPath := configManager.ProjectConfigPath(projectDir)
+	fmt.Fprintf(out, "User config:    %s%s\n", configManager.UserConfigPath(), existsSuffix(configManager.UserConfigPath()))
+	fmt.Fprintf(out, "Storage:        %s\n", configManager.Storage())
+	fmt.Fprintf(out, "Project:        %s\n", projectDir)
+	fmt.Fprintf(out, "Project config: %s%s\n", projectConfigPath, existsSuffix(projectConfigPath))
+	return 0
+}
+
+// configFlags parses the options shared by config get and set
+func configFlags(name string, args []string) (user bool, projectDir string, rest []string, ok bool) {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.BoolVar(&user, "user", false, "use the user config instead of the project's")
+	flags.StringVar(&projectDir, "project", ".", "project directory")
+	if err := flags.Parse(args); err != nil {
+		return false, "", nil, false
+	}
+	return user, projectDir, flags.Args(), true
+}
+
+func runConfigGet(args []string, out io.Writer) int {
+	user, projectDir, rest, ok := configFlags("config get", args)
+	if !ok || len(rest) > 1 {
+		fmt.Fprint(os.Stderr, cliUsage)
+		return 2
+	}
+
+	target, err := loadCLISettings(user, projectDir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	if len(rest) == 0 {
+		data, err := json.MarshalIndent(target.settings, "", "  ")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		fmt.Fprintln(out, string(data))
+		return 0
+	}
+
+	value, err := config.GetField(target.settings, rest[0])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 2
+	}
+	var text string
+	if json.Unmarshal(value, &text) == nil {
+		fmt.Fprintln(out, text) // strings are printed without quotes for scripts
+		return 0
+	}
+	fmt.Fprintln(out, string(value))
+	return 0
+}
+
+func runConfigSet(args []string, out io.Writer) int {
+	user, projectDir, rest, ok := configFlags("config set", args)
+	if !ok || len(rest) != 2 {
+		fmt.Fprint(os.Stderr, cliUsage)
+		return 2
+	}
+
+	target, err := loadCLISettings(user, projectDir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	if err := config.SetField(target.settings, rest[0], rest[1]); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 2
+	}
+	if err := target.save(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	fmt.Fprintf(out, "Set %s in %s\n", rest[0], target.path())
+	return 0
+}
+
+// cliSettings is the config file that config get and set work on
+type cliSettings struct {
+	manager    *config.ConfigManager
+	projectDir string      // empty for the user config
+	settings   interface{} // *types.UserConfig or *types.ProjectConfig
+}
+
+func loadCLISettings(user bool, projectDir string) (*cliSettings, error) {
+	configManager, err := config.NewConfigManager()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create config manager: %w", err)
+	}
+
+	if user {
+		// Settings cannot be merged into a file that does not parse
+		userConfig, err := configManager.LoadUserConfig()
+		if err != nil {
+			return nil, fmt.Errorf("%w; fix or remove the file by hand", err)
+		}
+		return &cliSettings{manager: configManager, settings: userConfig}, nil
+	}
+
+	resolved, err := cliProjectDir([]string{projectDir})
+	if err != nil {
+		return nil, err
+	}
+	projectConfig, err := configManager.ReadProjectConfig(resolved)
+	if err != nil {
+		return nil, err
+	}
+	return &cliSettings{
+		manager:    configManager,
+		projectDir: resolved,
+		settings:   projectConfig,
+	}, nil
+}
+
+func (c *cliSettings) path() string {
+	if c.projectDir == "" {
+		return c.manager.UserConfigPath()
+	}
+	return c.manager.ProjectConfigPath(c.projectDir)
+}
+
+func (c *cliSettings) save() error {
+	switch settings := c.settings.(type) {
+	case *types.UserConfig:
+		if err := validateUserConfig(settings); err != nil {
+			return err
+		}
+		return c.manager.SaveUserConfig(settings)
+	case *types.ProjectConfig:
+		return c.manager.SaveProjectConfig(c.projectDir, settings)
+	}
+	return fmt.Errorf("unsupported settings type %T", c.settings)
+}
+
+// validateUserConfig rejects values that would stop the server from starting
+func validateUserConfig(userConfig *types.UserConfig) error {
+	if _, err := gitignore.ParsePolicy(userConfig.GitignorePolicy); err != nil {
+		return err
+	}
+	switch userConfig.ConfigStorage {
+	case "", config.StorageProject, config.StorageUser:
+		return nil
+	}
+	return fmt.Errorf("unknown config_storage %q (expected project or user)", userConfig.ConfigStorage)
+}
+
+func runHistoryCommand(args []string, out io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprint(os.Stderr, cliUsage)
+		return 2
+	}
+
+	switch args[0] {
+	case "show":
+		return runHistoryShow(args[1:], out)
+	case "export":
+		return runHistoryExport(args[1:], out)
+	case "clear":
+		return runHistoryClear(args[1:], out)
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown history command: %s\n\n%s", args[0], cliUsage)
+		return 2
+	}
+}
+
+func runHistoryExport(args []string, out io.Writer) int {
+	flags := flag.NewFlagSet("history export", flag.ContinueOnError)
+	format := flags.String("format", "", "markdown, json or text; defaults to the output file's extension, else markdown")
+	output := flags.String("output", "", "file to write instead of stdout")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() > 1 {
+		// Flags after the project directory would otherwise be ignored
+		fmt.Fprint(os.Stderr, cliUsage)
+		return 2
+	}
+	if *format == "" {
+		*format = export.FormatForPath(*output)
+	}
+
+	configManager, err := config.NewConfigManager()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error creating config manager: %v\n", err)
+		return 1
+	}
+
+	projectDir, err := cliProjectDir(flags.Args())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	projectConfig, err := configManager.ReadProjectConfig(projectDir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	text, err := export.Render(export.Thread{Project: projectDir, Entries: projectConfig.ConversationHistory}, *format)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 2
+	}
+
+	if *output == "" {
+		fmt.Fprint(out, text)
+		return 0
+	}
+	if err := os.WriteFile(*output, []byte(text), 0644); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: failed to write %s: %v\n", *output, err)
+		return 1
+	}
+	return 0
+}
+
+func runHistoryShow(args []string, out io.Writer) int {
+	configManager, projectDir, code := cliProject(args)
+	if configManager == nil {
+		return code
+	}
+
+	projectConfig, err := configManager.ReadProjectConfig(projectDir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	history := projectConfig.ConversationHistory
+	if len(history) == 0 {
+		fmt.Fprintln(out, "No conversation history.")
+		return 0
+	}
+	fmt.Fprint(out, export.Text(export.Thread{Project: projectDir, Entries: history}))
+	return 0
+}
+
+func runHistoryClear(args []string, out io.Writer) int {
+	configManager, projectDir, code := cliProject(args)
+	if configManager == nil {
+		return code
+	}
+
+	// Saving defaults over a file that could not be read would lose the settings
+	projectConfig, err := configManager.ReadProjectConfig(projectDir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	cleared := len(projectConfig.ConversationHistory)
+	projectConfig.ConversationHistory = []types.ConversationEntry{}
+	if err := configManager.SaveProjectConfig(projectDir, projectConfig); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	fmt.Fprintf(out, "Cleared %d entries from %s\n", cleared, configManager.ProjectConfigPath(projectDir))
+	return 0
+}
+
+func runGitignoreCommand(args []string, out io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprint(os.Stderr, cliUsage)
+		return 2
+	}
+
+	switch args[0] {
+	case "install":
+		return runGitignoreInstall(args[1:], out)
+	case "remove":
+		return runGitignoreRemove(args[1:], out)
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown gitignore command: %s\n\n%s", args[0], cliUsage)
+		return 2
+	}
+}
+
+func runGitignoreInstall(args []string, out io.Writer) int {
+	configManager, projectDir, code := cliProject(args)
+	if configManager == nil {
+		return code
+	}
+	if configManager.Storage() != config.StorageProject {
+		fmt.Fprintln(out, "Project state is stored outside the project (config_storage is user); nothing to ignore.")
+		return 0
+	}
+
+	policy, code := cliGitignorePolicy(configManager)
+	if code != 0 {
+		return code
+	}
+	if policy == gitignore.PolicyOff {
+		policy = gitignore.PolicyGitignore // asked for explicitly
+	}
+
+	changed, err := gitignore.Ensure(projectDir, config.ProjectConfigFileName, policy)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	switch {
+	case changed != "":
+		fmt.Fprintf(out, "Added %s to %s\n", config.ProjectConfigFileName, changed)
+	case isGitRepository(projectDir):
+		fmt.Fprintf(out, "%s is already ignored.\n", config.ProjectConfigFileName)
+	default:
+		fmt.Fprintln(out, "Not inside a git repository; nothing to do.")
+	}
+	return 0
+}
+
+func runGitignoreRemove(args []string, out io.Writer) int {
+	configManager, projectDir, code := cliProject(args)
+	if configManager == nil {
+		return code
+	}
+
+	changed, err := gitignore.Remove(projectDir, config.ProjectConfigFileName)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	if len(changed) == 0 {
+		fmt.Fprintln(out, "No entries added by Interactive Feedback MCP were found.")
+		return 0
+	}
+	for _, path := range changed {
+		fmt.Fprintf(out, "Removed %s from %s\n", config.ProjectConfigFileName, path)
+	}
+
+	if policy, code := cliGitignorePolicy(configManager); code == 0 && policy != gitignore.PolicyOff && configManager.Storage() == config.StorageProject {
+		fmt.Fprintln(out, "The server adds the entry again on its next call unless gitignore_policy is off (mcp-server-single config set --user gitignore_policy off).")
+	}
+	return 0
+}
+
+// cliProject creates the config manager and resolves the optional project
+// argument, returning a nil manager and the exit code on failure
+func cliProject(args []string) (*config.ConfigManager, string, int) {
+	if len(args) > 1 {
+		fmt.Fprint(os.Stderr, cliUsage)
+		return nil, "", 2
+	}
+
+	configManager, err := config.NewConfigManager()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error creating config manager: %v\n", err)
+		return nil, "", 1
+	}
+
+	projectDir, err := cliProjectDir(args)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return nil, "", 1
+	}
+	return configManager, projectDir, 0
+}
+
+func cliGitignorePolicy(configManager *config.ConfigManager) (gitignore.Policy, int) {
+	userConfig, err := configManager.LoadUserConfig()
+	if err == nil {
+		var policy gitignore.Policy
+		if policy, err = gitignore.ParsePolicy(userConfig.GitignorePolicy); err == nil {
+			return policy, 0
+		}
+	}
+	fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+	return "", 1
+}
+
+func isGitRepository(dir string) bool {
+	repo, err := gitignore.FindRepository(dir)
+	return err == nil && repo != nil
+}
+
+// cliProjectDir resolves the optional project argument, defaulting to the
+// working directory. There is no MCP client here, so only the user's
+// allowed_roots apply; strict_roots is about client roots and is skipped.
+func cliProjectDir(args []string) (string, error) {
+	projectDir := "."
+	if len(args) > 0 {
+		projectDir = args[0]
+	}
+
+	projectDir, err := filepath.Abs(projectDir)
+	if err != nil {
+		return "", err
+	}
+
+	configManager, err := config.NewConfigManager()
+	if err != nil {
+		return "", fmt.Errorf("error creating config manager: %w", err)
+	}
+	// The user runs the CLI themselves, so a broken user config only warns
+	var allowedRoots []string
+	if userConfig, err := configManager.LoadUserConfig(); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v; ignoring allowed_roots\n", err)
+	} else {
+		allowedRoots = userConfig.AllowedRoots
+	}
+	return workspace.Resolve(projectDir, nil, allowedRoots)
+}
+
+func existsSuffix(path string) string {
+	if _, err := os.Stat(path); err != nil {
+		return " (not created yet)"
+	}
+	return ""
+}
@@ -0,0 +1,260 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"interactive-feedback-mcp/internal/config"
+	"interactive-feedback-mcp/internal/types"
+)
+
+// newCLIProject isolates the user config and returns a project directory
+// holding a short conversation
+func newCLIProject(t *testing.T) (*config.ConfigManager, string) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+
+	projectDir, err := filepath.EvalSymlinks(t.TempDir())
+	require.NoError(t, err)
+
+	manager, err := config.NewConfigManager()
+	require.NoError(t, err)
+	timestamp := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	require.NoError(t, manager.SaveProjectConfig(projectDir, &types.ProjectConfig{
+		ConversationHistory: []types.ConversationEntry{
+			{ID: "1", Timestamp: timestamp, Role: "assistant", Content: "Ready to merge?"},
+			{ID: "2", Timestamp: timestamp, Role: "user", Content: "Yes, ship it"},
+		},
+	}))
+	return manager, projectDir
+}
+
+func runCLIArgs(t *testing.T, args ...string) (int, string) {
+	t.Helper()
+	var out bytes.Buffer
+	var code int
+	switch args[0] {
+	case "config":
+		code = runConfigCommand(args[1:], &out)
+	case "history":
+		code = runHistoryCommand(args[1:], &out)
+	case "gitignore":
+		code = runGitignoreCommand(args[1:], &out)
+	default:
+		t.Fatalf("unexpected command %s", args[0])
+	}
+	return code, out.String()
+}
+
+func TestCLI_History(t *testing.T) {
+	manager, projectDir := newCLIProject(t)
+
+	code, out := runCLIArgs(t, "history", "show", projectDir)
+	assert.Equal(t, 0, code)
+	assert.Contains(t, out, "Assistant:\nReady to merge?")
+	assert.Contains(t, out, "User:\nYes, ship it")
+
+	code, out = runCLIArgs(t, "history", "export", "--format", "json", projectDir)
+	assert.Equal(t, 0, code)
+	var thread struct {
+		Project string                    `json:"project"`
+		Entries []types.ConversationEntry `json:"entries"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(out), &thread))
+	assert.Equal(t, projectDir, thread.Project)
+	assert.Len(t, thread.Entries, 2)
+
+	// The format follows the output file's extension
+	output := filepath.Join(t.TempDir(), "thread.md")
+	code, _ = runCLIArgs(t, "history", "export", "--output", output, projectDir)
+	assert.Equal(t, 0, code)
+	data, err := os.ReadFile(output)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "# Conversation: "+projectDir)
+
+	// Flags after the project directory are rejected rather than ignored
+	code, _ = runCLIArgs(t, "history", "export", projectDir, "--format", "json")
+	assert.Equal(t, 2, code)
+	code, _ = runCLIArgs(t, "history", "export", "--format", "yaml", projectDir)
+	assert.Equal(t, 2, code)
+
+	code, out = runCLIArgs(t, "history", "clear", projectDir)
+	assert.Equal(t, 0, code)
+	assert.Contains(t, out, "Cleared 2 entries")
+	assert.Empty(t, manager.LoadProjectConfig(projectDir).ConversationHistory)
+
+	code, out = runCLIArgs(t, "history", "show", projectDir)
+	assert.Equal(t, 0, code)
+	assert.Equal(t, "No conversation history.\n", out)
+}
+
+func TestCLI_IgnoresStrictRoots(t *testing.T) {
+	manager, projectDir := newCLIProject(t)
+	require.NoError(t, manager.SaveUserConfig(&types.UserConfig{StrictRoots: true}))
+
+	// The CLI has no client roots, so strict_roots does not apply
+	code, _ := runCLIArgs(t, "history", "show", projectDir)
+	assert.Equal(t, 0, code)
+
+	// allowed_roots still does
+	require.NoError(t, manager.SaveUserConfig(&types.UserConfig{AllowedRoots: []string{t.TempDir()}}))
+	code, _ = runCLIArgs(t, "history", "show", projectDir)
+	assert.Equal(t, 1, code)
+}
+
+func TestCLI_ConfigLocate(t *testing.T) {
+	manager, projectDir := newCLIProject(t)
+
+	code, out := runCLIArgs(t, "config", "locate", projectDir)
+	assert.Equal(t, 0, code)
+	assert.Contains(t, out, "Storage:        project\n")
+	assert.Contains(t, out, "Project config: "+filepath.Join(projectDir, config.ProjectConfigFileName)+"\n")
+
+	require.NoError(t, manager.SaveUserConfig(&types.UserConfig{ConfigStorage: config.StorageUser}))
+	code, out = runCLIArgs(t, "config", "locate", projectDir)
+	assert.Equal(t, 0, code)
+	assert.Contains(t, out, "Storage:        user\n")
+
+	// A broken user config falls back to project storage
+	require.NoError(t, os.WriteFile(manager.UserConfigPath(), []byte("{broken"), 0644))
+	code, out = runCLIArgs(t, "config", "locate", projectDir)
+	assert.Equal(t, 0, code)
+	assert.Contains(t, out, "Storage:        project\n")
+}
+
+func TestCLI_Config(t *testing.T) {
+	manager, projectDir := newCLIProject(t)
+
+	code, out := runCLIArgs(t, "config", "set", "--project", projectDir, "run_command", "make test")
+	assert.Equal(t, 0, code)
+	assert.Contains(t, out, "Set run_command in "+manager.ProjectConfigPath(projectDir))
+	assert.Equal(t, "make test", manager.LoadProjectConfig(projectDir).RunCommand)
+
+	code, out = runCLIArgs(t, "config", "get", "--project", projectDir, "run_command")
+	assert.Equal(t, 0, code)
+	assert.Equal(t, "make test\n", out)
+
+	// Setting one value keeps the history
+	assert.Len(t, manager.LoadProjectConfig(projectDir).ConversationHistory, 2)
+
+	code, _ = runCLIArgs(t, "config", "set", "--user", "allowed_roots", `["/work"]`)
+	assert.Equal(t, 0, code)
+	code, out = runCLIArgs(t, "config", "get", "--user", "allowed_roots")
+	assert.Equal(t, 0, code)
+	assert.Equal(t, "[\"/work\"]\n", out)
+
+	// User settings that would break the server are refused
+	code, _ = runCLIArgs(t, "config", "set", "--user", "config_storage", "cloud")
+	assert.Equal(t, 1, code)
+	code, _ = runCLIArgs(t, "config", "set", "--user", "gitignore_policy", "sometimes")
+	assert.Equal(t, 1, code)
+	userConfig, err := manager.LoadUserConfig()
+	require.NoError(t, err)
+	assert.Empty(t, userConfig.ConfigStorage)
+	assert.Empty(t, userConfig.GitignorePolicy)
+
+	// A broken user config can be repaired through the CLI
+	require.NoError(t, os.WriteFile(manager.UserConfigPath(), []byte(`{"config_storage": "cloud"}`), 0644))
+	code, _ = runCLIArgs(t, "config", "set", "--user", "config_storage", "project")
+	assert.Equal(t, 0, code)
+
+	code, _ = runCLIArgs(t, "config", "get", "--user", "nope")
+	assert.Equal(t, 2, code)
+	code, _ = runCLIArgs(t, "config", "set", "--user", "strict_roots", "maybe")
+	assert.Equal(t, 2, code)
+}
+
+func TestCLI_Gitignore(t *testing.T) {
+	_, projectDir := newCLIProject(t)
+	if _, err := exec.LookPath("git"); err == nil {
+		output, err := exec.Command("git", "init", "-q", "--template=", projectDir).CombinedOutput()
+		require.NoError(t, err, string(output))
+	}
+	require.NoError(t, os.MkdirAll(filepath.Join(projectDir, ".git", "info"), 0755))
+	gitignorePath := filepath.Join(projectDir, ".gitignore")
+
+	code, out := runCLIArgs(t, "gitignore", "install", projectDir)
+	assert.Equal(t, 0, code)
+	assert.Equal(t, "Added "+config.ProjectConfigFileName+" to "+gitignorePath+"\n", out)
+
+	code, out = runCLIArgs(t, "gitignore", "install", projectDir)
+	assert.Equal(t, 0, code)
+	assert.Contains(t, out, "already ignored")
+
+	code, out = runCLIArgs(t, "gitignore", "remove", projectDir)
+	assert.Equal(t, 0, code)
+	assert.Contains(t, out, "Removed "+config.ProjectConfigFileName+" from "+gitignorePath)
+	content, err := os.ReadFile(gitignorePath)
+	require.NoError(t, err)
+	assert.NotContains(t, string(content), config.ProjectConfigFileName)
+
+	code, out = runCLIArgs(t, "gitignore", "remove", projectDir)
+	assert.Equal(t, 0, code)
+	assert.Contains(t, out, "No entries")
+}
+
+func TestCLI_BrokenUserConfig(t *testing.T) {
+	manager, projectDir := newCLIProject(t)
+	require.NoError(t, os.MkdirAll(manager.UserConfigDir(), 0755))
+	require.NoError(t, os.WriteFile(manager.UserConfigPath(), []byte("{broken"), 0644))
+
+	// Project commands still work
+	code, _ := runCLIArgs(t, "history", "show", projectDir)
+	assert.Equal(t, 0, code)
+	code, _ = runCLIArgs(t, "config", "set", "--project", projectDir, "run_command", "make test")
+	assert.Equal(t, 0, code)
+
+	// The user config itself is not overwritten with defaults
+	code, _ = runCLIArgs(t, "config", "set", "--user", "strict_roots", "true")
+	assert.Equal(t, 1, code)
+	data, err := os.ReadFile(manager.UserConfigPath())
+	require.NoError(t, err)
+	assert.Equal(t, "{broken", string(data))
+}
+
+func TestCLI_RefusesToOverwriteUnreadableConfig(t *testing.T) {
+	manager, projectDir := newCLIProject(t)
+	broken := []byte(`{"run_command": "make test", "conversation_history": "not a list"}`)
+	require.NoError(t, os.WriteFile(manager.ProjectConfigPath(projectDir), broken, 0644))
+
+	code, _ := runCLIArgs(t, "history", "clear", projectDir)
+	assert.Equal(t, 1, code)
+	code, _ = runCLIArgs(t, "config", "set", "--project", projectDir, "run_command", "make lint")
+	assert.Equal(t, 1, code)
+	code, _ = runCLIArgs(t, "history", "show", projectDir)
+	assert.Equal(t, 1, code)
+
+	data, err := os.ReadFile(manager.ProjectConfigPath(projectDir))
+	require.NoError(t, err)
+	assert.Equal(t, broken, data)
+}
+
+func TestConfigChecks(t *testing.T) {
+	manager, projectDir := newCLIProject(t)
+
+	statuses := func() map[string][]string {
+		found := make(map[string][]string)
+		for _, check := range configChecks([]string{projectDir}) {
+			found[check.name] = append(found[check.name], check.status)
+		}
+		return found
+	}
+	assert.Equal(t, []string{checkOK}, statuses()["user config"])
+	assert.Equal(t, []string{checkOK}, statuses()["project config"])
+
+	// A project config the server cannot read fails, even if it is valid JSON
+	require.NoError(t, os.WriteFile(manager.ProjectConfigPath(projectDir), []byte(`{"run_command": 1}`), 0644))
+	assert.Equal(t, []string{checkFail}, statuses()["project config"])
+
+	// A user config problem is reported once
+	require.NoError(t, manager.SaveUserConfig(&types.UserConfig{ConfigStorage: "cloud"}))
+	assert.Equal(t, []string{checkFail}, statuses()["user config"])
+}
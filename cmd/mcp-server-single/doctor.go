@@ -0,0 +1,164 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"interactive-feedback-mcp/internal/config"
+	"interactive-feedback-mcp/internal/gitignore"
+)
+
+// Doctor check outcomes
+const (
+	checkOK   = "ok"
+	checkWarn = "warn"
+	checkFail = "FAIL"
+)
+
+// doctorCheck is one line of the doctor report
+type doctorCheck struct {
+	status string
+	name   string
+	detail string
+}
+
+// runDoctor checks what the server needs to show the GUI and to keep the
+// project's state. It exits with 1 if any check failed.
+func runDoctor(args []string, out io.Writer) int {
+	if len(args) > 1 {
+		fmt.Fprint(os.Stderr, cliUsage)
+		return 2
+	}
+
+	checks := pythonChecks()
+	checks = append(checks, displayCheck(), gitCheck())
+	checks = append(checks, configChecks(args)...)
+
+	failed := false
+	for _, check := range checks {
+		fmt.Fprintf(out, "%-5s %s: %s\n", check.status, check.name, check.detail)
+		failed = failed || check.status == checkFail
+	}
+	if failed {
+		return 1
+	}
+	return 0
+}
+
+// pythonChecks looks for the GUI script, Python, Tkinter and the optional modules
+func pythonChecks() []doctorCheck {
+	var checks []doctorCheck
+	if desktopGUI, err := findDesktopGUI(); err != nil {
+		checks = append(checks, doctorCheck{checkFail, "desktop GUI", err.Error()})
+	} else {
+		checks = append(checks, doctorCheck{checkOK, "desktop GUI", desktopGUI})
+	}
+
+	version, err := exec.Command("python3", "--version").CombinedOutput()
+	if err != nil {
+		return append(checks, doctorCheck{checkFail, "python3", "not found on PATH"})
+	}
+	checks = append(checks, doctorCheck{checkOK, "python3", strings.TrimSpace(string(version))})
+
+	for _, module := range []struct {
+		name, importName, missing string
+		required                  bool
+	}{
+		{"tkinter", "tkinter", "not installed; the GUI cannot start", true},
+		{"Pillow", "PIL", "not installed; pasting images and showing JPEG or WebP attachments are unavailable", false},
+		{"tkinterdnd2", "tkinterdnd2", "not installed; dropping files onto the window is unavailable", false},
+	} {
+		switch {
+		case exec.Command("python3", "-c", "import "+module.importName).Run() == nil:
+			checks = append(checks, doctorCheck{checkOK, module.name, "installed"})
+		case module.required:
+			checks = append(checks, doctorCheck{checkFail, module.name, module.missing})
+		default:
+			checks = append(checks, doctorCheck{checkWarn, module.name, module.missing})
+		}
+	}
+	return checks
+}
+
+// displayCheck reports whether a GUI can open; only Linux needs a display set
+func displayCheck() doctorCheck {
+	if runtime.GOOS != "linux" {
+		return doctorCheck{checkOK, "display", runtime.GOOS}
+	}
+	for _, name := range []string{"WAYLAND_DISPLAY", "DISPLAY"} {
+		if value := os.Getenv(name); value != "" {
+			return doctorCheck{checkOK, "display", name + "=" + value}
+		}
+	}
+	return doctorCheck{checkFail, "display", "neither DISPLAY nor WAYLAND_DISPLAY is set; the GUI cannot open"}
+}
+
+// gitCheck looks for git, which request_diff_review uses when no diff is given
+func gitCheck() doctorCheck {
+	version, err := exec.Command("git", "--version").Output()
+	if err != nil {
+		return doctorCheck{checkWarn, "git", "not found; request_diff_review needs an explicit diff"}
+	}
+	return doctorCheck{checkOK, "git", strings.TrimSpace(string(version))}
+}
+
+// configChecks validates the user config and the project's config file
+func configChecks(args []string) []doctorCheck {
+	configManager, err := config.NewConfigManager()
+	if err != nil {
+		return []doctorCheck{{checkFail, "user config", err.Error()}}
+	}
+	var checks []doctorCheck
+	userConfig, err := configManager.LoadUserConfig()
+	if err == nil {
+		err = validateUserConfig(userConfig)
+	}
+	if err != nil {
+		checks = append(checks, doctorCheck{checkFail, "user config", err.Error()})
+	} else {
+		checks = append(checks, doctorCheck{checkOK, "user config", configManager.UserConfigPath() + existsSuffix(configManager.UserConfigPath())})
+	}
+	if userConfig == nil {
+		return checks
+	}
+	policy, _ := gitignore.ParsePolicy(userConfig.GitignorePolicy)
+
+	projectDir, err := cliProjectDir(args)
+	if err != nil {
+		return append(checks, doctorCheck{checkFail, "project", err.Error()})
+	}
+	checks = append(checks, doctorCheck{checkOK, "project", projectDir})
+
+	projectConfigPath := configManager.ProjectConfigPath(projectDir)
+	if _, err := configManager.ReadProjectConfig(projectDir); err != nil {
+		checks = append(checks, doctorCheck{checkFail, "project config", fmt.Sprintf("%v; the server ignores it and will overwrite it", err)})
+	} else {
+		checks = append(checks, doctorCheck{checkOK, "project config", projectConfigPath + existsSuffix(projectConfigPath)})
+	}
+
+	if configManager.Storage() != config.StorageProject || policy == gitignore.PolicyOff {
+		return checks
+	}
+	repo, err := gitignore.FindRepository(projectDir)
+	switch {
+	case err != nil:
+		checks = append(checks, doctorCheck{checkWarn, "gitignore", err.Error()})
+	case repo == nil:
+		checks = append(checks, doctorCheck{checkOK, "gitignore", "not a git repository"})
+	default:
+		ignored, err := repo.IsIgnored(projectConfigPath)
+		switch {
+		case err != nil:
+			checks = append(checks, doctorCheck{checkWarn, "gitignore", err.Error()})
+		case ignored:
+			checks = append(checks, doctorCheck{checkOK, "gitignore", config.ProjectConfigFileName + " is ignored"})
+		default:
+			checks = append(checks, doctorCheck{checkWarn, "gitignore", config.ProjectConfigFileName + " is not ignored yet; run mcp-server-single gitignore install"})
+		}
+	}
+	return checks
+}
@@ -0,0 +1,382 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"interactive-feedback-mcp/internal/attachments"
+	"interactive-feedback-mcp/internal/config"
+	"interactive-feedback-mcp/internal/export"
+	"interactive-feedback-mcp/internal/gitignore"
+	"interactive-feedback-mcp/internal/tools"
+	"interactive-feedback-mcp/internal/types"
+)
+
+func registerTools() {
+	mustRegister(tools.Tool{
+		Name:        "interactive_feedback",
+		Description: "Get interactive feedback from user for development tasks",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"projectDirectory": projectDirectoryProperty(),
+				"prompt": map[string]interface{}{
+					"type":        "string",
+					"description": "The prompt to show to the user",
+				},
+				"previousUserRequest": map[string]interface{}{
+					"type":        "string",
+					"description": "The previous user request that triggered this interactive feedback",
+				},
+				"runCommand": map[string]interface{}{
+					"type":        "string",
+					"enum":        []string{runCommandOff, runCommandBefore, runCommandParallel},
+					"description": "Run the project's configured command before showing the popup or while the user answers, and return its output in command_logs. Defaults to the project's execute_automatically setting",
+				},
+				"runCommands": map[string]interface{}{
+					"type":        "array",
+					"items":       map[string]interface{}{"type": "string"},
+					"description": "Names of the project's command profiles to run (e.g. test, lint) instead of the default one. They run while the user answers unless runCommand says otherwise",
+				},
+				"attachments": map[string]interface{}{
+					"type": "array",
+					"items": map[string]interface{}{
+						"type": "object",
+						"properties": map[string]interface{}{
+							"type": map[string]interface{}{
+								"type":        "string",
+								"enum":        []string{attachments.PromptImage, attachments.PromptFile, attachments.PromptSnippet},
+								"description": "image: base64 data; file: a file inside the project; snippet: text with an optional language",
+							},
+							"name":     map[string]interface{}{"type": "string", "description": "Label shown to the user"},
+							"data":     map[string]interface{}{"type": "string", "description": "Base64 PNG, JPEG, GIF or WebP data, for images"},
+							"mimeType": map[string]interface{}{"type": "string", "description": "The image's MIME type; detected from the data when omitted"},
+							"path":     map[string]interface{}{"type": "string", "description": "Path of the file, relative to projectDirectory"},
+							"text":     map[string]interface{}{"type": "string", "description": "The snippet's text"},
+							"language": map[string]interface{}{"type": "string", "description": "The snippet's language, e.g. go or sql"},
+						},
+						"required":             []string{"type"},
+						"additionalProperties": false,
+					},
+					"description": "Images, project files or text snippets shown to the user next to the prompt",
+				},
+			},
+			"required":             []string{"prompt", "previousUserRequest"},
+			"additionalProperties": false,
+		},
+		Handler: handleInteractiveFeedback,
+	})
+	registerProjectCommandTool()
+	registerJobTools()
+	registerReviewTool()
+	registerPlanTool()
+}
+
+func handleInteractiveFeedback(call *tools.Call) (*tools.Result, error) {
+	// Extract arguments (types were checked against the input schema)
+	projectDir, _ := call.Arguments["projectDirectory"].(string)
+	prompt, _ := call.Arguments["prompt"].(string)
+	previousUserRequest, _ := call.Arguments["previousUserRequest"].(string)
+	runMode, _ := call.Arguments["runCommand"].(string)
+	var runCommands []string
+	if names, ok := call.Arguments["runCommands"].([]interface{}); ok {
+		for _, name := range names {
+			runCommands = append(runCommands, name.(string))
+		}
+	}
+
+	projectDir, err := resolveProjectDir(projectDir)
+	if err != nil {
+		return nil, tools.InvalidParams("%v", err)
+	}
+
+	// Follow this project's config for enabled tools
+	watchProject(projectDir)
+
+	promptFiles, err := promptAttachments(projectDir, call.Arguments["attachments"])
+	if err != nil {
+		return nil, tools.InvalidParams("%v", err)
+	}
+
+	// Reject unknown profile names before showing anything
+	if len(runCommands) > 0 {
+		configManager, err := config.NewConfigManager()
+		if err != nil {
+			return nil, err
+		}
+		if _, _, err := selectCommandProfiles(runMode, runCommands, configManager.LoadProjectConfig(projectDir)); err != nil {
+			return nil, tools.InvalidParams("%v", err)
+		}
+	}
+
+	// Run interactive feedback with single popup GUI
+	text, attachmentBlocks := runInteractiveFeedbackWithSinglePopupGUI(projectDir, prompt, previousUserRequest, runMode, runCommands, promptFiles)
+
+	result := tools.TextResult(text)
+	result.Content = append(result.Content, attachmentBlocks...)
+	return result, nil
+}
+
+// runInteractiveFeedbackWithSinglePopupGUI returns the feedback result as
+// JSON, followed by a content block for each file the user attached
+func runInteractiveFeedbackWithSinglePopupGUI(projectDir, prompt, previousUserRequest, runMode string, runCommands []string, promptFiles []*attachments.File) (string, []map[string]interface{}) {
+	// Load or create config
+	configManager, err := config.NewConfigManager()
+	if err != nil {
+		return fmt.Sprintf("Error creating config manager: %v", err), nil
+	}
+	
+	projectConfig := configManager.LoadProjectConfig(projectDir)
+	if projectConfig == nil {
+		projectConfig = &types.ProjectConfig{
+			RunCommand:              "",
+			ExecuteAutomatically:    false,
+			CommandSectionVisible:   true,
+			ConversationHistory:     []types.ConversationEntry{},
+		}
+	}
+
+	// STEP 1: Add previous user request to conversation history FIRST
+	if previousUserRequest != "" {
+		userEntry := types.ConversationEntry{
+			ID:        uuid.New().String(),
+			Timestamp: time.Now(),
+			Role:      "user",
+			Content:   previousUserRequest,
+			IsCurrent: false,
+		}
+		projectConfig.ConversationHistory = append(projectConfig.ConversationHistory, userEntry)
+	}
+
+	// STEP 2: Add agent prompt to conversation history
+	assistantEntry := types.ConversationEntry{
+		ID:        uuid.New().String(),
+		Timestamp: time.Now(),
+		Role:      "assistant",
+		Content:   prompt,
+		IsCurrent: false,
+	}
+	if len(promptFiles) > 0 {
+		assistantEntry.Payload = &types.AttachmentPayload{Attachments: attachmentSummary(promptFiles)}
+	}
+	projectConfig.ConversationHistory = append(projectConfig.ConversationHistory, assistantEntry)
+
+	// STEP 2.5: Trim conversation history to prevent file bloat (keep last 10 entries)
+	projectConfig.ConversationHistory = trimConversationHistory(projectConfig.ConversationHistory, 10)
+
+	// STEP 3: Keep the config file out of git if not already ignored
+	ensureGitignoreEntry(configManager, projectDir)
+
+	// STEP 4: Save config to disk BEFORE calling GUI
+	configManager.SaveProjectConfig(projectDir, projectConfig)
+
+	desktopGUI, err := findDesktopGUI()
+	if err != nil {
+		return err.Error(), nil
+	}
+
+	// Run the project's commands before or while the user answers
+	var runs []*commandRun
+	mode, profiles, err := selectCommandProfiles(runMode, runCommands, projectConfig)
+	if err != nil {
+		return err.Error(), nil
+	}
+	for _, profile := range profiles {
+		runs = append(runs, startApprovedCommand(configManager, desktopGUI, projectDir, projectConfig, profile))
+	}
+	if mode == runCommandBefore {
+		for _, run := range runs {
+			run.wait()
+		}
+	}
+
+	// The GUI lists attached files, and saves pasted images, in this
+	// directory. It also finds the conversation exports to copy or save here.
+	attachmentsDir, err := os.MkdirTemp("", "interactive-feedback-attachments-*")
+	if err != nil {
+		return fmt.Sprintf("Error creating attachments directory: %v", err), nil
+	}
+	defer os.RemoveAll(attachmentsDir)
+	writeConversationExports(attachmentsDir, export.Thread{Project: projectDir, Entries: projectConfig.ConversationHistory})
+
+	// STEP 4: Launch single popup desktop GUI AFTER saving config
+	args := []string{desktopGUI, projectDir, prompt, configManager.ProjectConfigPath(projectDir),
+		filepath.Join(attachmentsDir, attachmentsFileName)}
+	if len(promptFiles) > 0 {
+		promptFile, err := writePromptAttachments(attachmentsDir, promptFiles)
+		if err != nil {
+			return err.Error(), nil
+		}
+		args = append(args, promptFile)
+	}
+	cmd := exec.Command("python3", args...)
+	cmd.Dir = filepath.Dir(desktopGUI)
+	
+	// Capture output
+	output, err := cmd.Output()
+	if err != nil {
+		for _, run := range runs {
+			run.stop()
+		}
+		return fmt.Sprintf("Error running single popup desktop GUI: %v", err), nil
+	}
+	
+	userFeedback := strings.TrimSpace(string(output))
+	// Allow empty feedback - user can choose not to provide feedback
+	files, attachmentErrors := readAttachments(attachmentsDir)
+	
+	// STEP 5: Add user feedback to conversation only if feedback is provided
+	if userFeedback != "" || len(files) > 0 {
+		feedbackEntry := types.ConversationEntry{
+			ID:        uuid.New().String(),
+			Timestamp: time.Now(),
+			Role:      "user",
+			Content:   userFeedback,
+			IsCurrent: false,
+		}
+		if len(files) > 0 {
+			feedbackEntry.Payload = &types.AttachmentPayload{Attachments: attachmentSummary(files)}
+		}
+
+		projectConfig.ConversationHistory = append(projectConfig.ConversationHistory, feedbackEntry)
+
+		// Trim conversation history again after adding feedback
+		projectConfig.ConversationHistory = trimConversationHistory(projectConfig.ConversationHistory, 10)
+	}
+
+	// Save updated config with user feedback
+	configManager.SaveProjectConfig(projectDir, projectConfig)
+
+	// Create feedback result
+	feedbackResult := types.FeedbackResult{
+		CommandLogs:         "",
+		InteractiveFeedback: userFeedback,
+		Attachments:         attachmentSummary(files),
+		AttachmentErrors:    attachmentErrors,
+		ConversationHistory: projectConfig.ConversationHistory,
+	}
+
+	// Include the command results once they have finished
+	if len(runs) > 0 {
+		summarizeRuns(&feedbackResult, runs)
+	}
+
+	// Convert to JSON
+	resultBytes, err := json.MarshalIndent(feedbackResult, "", "  ")
+	if err != nil {
+		return fmt.Sprintf("Error creating feedback result: %v", err), nil
+	}
+
+	blocks := make([]map[string]interface{}, len(files))
+	for i, file := range files {
+		blocks[i] = attachmentContent(file)
+	}
+	return string(resultBytes), blocks
+}
+
+// writeConversationExports renders the history in every export format as
+// conversation.md, conversation.json and conversation.txt in dir, so that
+// the GUI copies and saves the same text as the other interfaces
+func writeConversationExports(dir string, thread export.Thread) {
+	for format, extension := range export.Formats {
+		text, err := export.Render(thread, format)
+		if err == nil {
+			err = os.WriteFile(filepath.Join(dir, "conversation"+extension), []byte(text), 0600)
+		}
+		if err != nil {
+			log.Printf("failed to export conversation as %s: %v", format, err)
+		}
+	}
+}
+
+// findDesktopGUI locates desktop_gui_single.py next to the executable or one level up
+func findDesktopGUI() (string, error) {
+	execPath, err := os.Executable()
+	if err != nil {
+		return "", fmt.Errorf("Error getting executable path: %v", err)
+	}
+
+	execDir := filepath.Dir(execPath)
+	for _, candidate := range []string{
+		filepath.Join(execDir, "desktop_gui_single.py"),
+		filepath.Join(execDir, "..", "desktop_gui_single.py"),
+	} {
+		if _, err := os.Stat(candidate); err == nil {
+			return candidate, nil
+		}
+	}
+	return "", fmt.Errorf("Single popup desktop GUI not found. Please ensure desktop_gui_single.py is in the project directory.")
+}
+
+// runGUIWithRequest runs one of the GUI's structured windows, handing it the
+// request as a JSON file. It returns the trimmed output, or nil if the user
+// closed the window without answering.
+func runGUIWithRequest(desktopGUI, mode string, request interface{}) ([]byte, error) {
+	requestFile, err := os.CreateTemp("", "interactive-feedback-request-*.json")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GUI request: %w", err)
+	}
+	defer os.Remove(requestFile.Name())
+
+	err = json.NewEncoder(requestFile).Encode(request)
+	if closeErr := requestFile.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to write GUI request: %w", err)
+	}
+
+	cmd := exec.Command("python3", desktopGUI, mode, requestFile.Name())
+	cmd.Dir = filepath.Dir(desktopGUI)
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to run GUI: %w", err)
+	}
+
+	output = []byte(strings.TrimSpace(string(output)))
+	if len(output) == 0 {
+		return nil, nil
+	}
+	return output, nil
+}
+
+func trimConversationHistory(history []types.ConversationEntry, maxEntries int) []types.ConversationEntry {
+	if len(history) <= maxEntries {
+		return history
+	}
+	
+	// Keep the last maxEntries entries
+	startIndex := len(history) - maxEntries
+	return history[startIndex:]
+}
+
+// ensureGitignoreEntry keeps the project config file out of version control
+// according to the user's gitignore policy
+func ensureGitignoreEntry(configManager *config.ConfigManager, projectDir string) {
+	if configManager.Storage() != config.StorageProject {
+		return // Nothing is written into the project
+	}
+
+	userConfig, err := configManager.LoadUserConfig()
+	if err != nil {
+		log.Printf("Skipping gitignore update: %v", err)
+		return
+	}
+
+	policy, err := gitignore.ParsePolicy(userConfig.GitignorePolicy)
+	if err != nil {
+		log.Printf("Skipping gitignore update: %v", err)
+		return
+	}
+
+	if _, err := gitignore.Ensure(projectDir, config.ProjectConfigFileName, policy); err != nil {
+		log.Printf("Error updating ignore rules: %v", err)
+	}
+}
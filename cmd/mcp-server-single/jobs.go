@@ -0,0 +1,309 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"interactive-feedback-mcp/internal/executor"
+	"interactive-feedback-mcp/internal/tools"
+	"interactive-feedback-mcp/internal/types"
+)
+
+// defaultTailChunks is how much output tail_background_command returns without "since"
+const defaultTailChunks = 200
+
+// jobShutdownTimeout bounds the graceful stop of background jobs when the session ends
+const jobShutdownTimeout = 5 * time.Second
+
+// jobKillWait is how long kill_background_command waits for the job to exit
+const jobKillWait = 10 * time.Second
+
+// maxExitedJobs is how many exited jobs are kept for listing and tailing;
+// older ones are dropped with their output when a new job starts
+const maxExitedJobs = 20
+
+// backgroundJob is a command started with start_background_command
+type backgroundJob struct {
+	id         string
+	seq        int // start order
+	profile    string
+	command    string
+	projectDir string
+	handle     *types.CommandHandle
+	exited     chan struct{} // closed once handle.Result is set
+}
+
+var (
+	jobExecutor = executor.NewCommandExecutor()
+	jobsMutex   sync.Mutex
+	jobs        = make(map[string]*backgroundJob)
+	nextJobID   int
+)
+
+// jobStatus is how jobs are reported to the agent
+type jobStatus struct {
+	ID         string `json:"id"`
+	Profile    string `json:"profile"`
+	Command    string `json:"command"`
+	ProjectDir string `json:"project_directory"`
+	PID        int    `json:"pid"`
+	Running    bool   `json:"running"`
+	StartTime  string `json:"start_time"`
+	ExitCode   *int   `json:"exit_code,omitempty"`
+	Signal     string `json:"signal,omitempty"`
+	DurationMs int64  `json:"duration_ms,omitempty"`
+}
+
+func registerJobTools() {
+	mustRegister(tools.Tool{
+		Name:        "start_background_command",
+		Description: "Start one of the project's configured commands in the background (e.g. a dev server or watcher) and return its job id. The user approves each command the first time it runs. Jobs are stopped when the session ends",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"projectDirectory": projectDirectoryProperty(),
+				"profile": map[string]interface{}{
+					"type":        "string",
+					"description": "Name of the command profile to start; defaults to the project's default command",
+				},
+			},
+			"additionalProperties": false,
+		},
+		Handler: handleStartBackgroundCommand,
+	})
+
+	mustRegister(tools.Tool{
+		Name:        "list_background_commands",
+		Description: "List the background jobs of this session with their status",
+		InputSchema: map[string]interface{}{
+			"type":                 "object",
+			"properties":           map[string]interface{}{},
+			"additionalProperties": false,
+		},
+		Handler: handleListBackgroundCommands,
+	})
+
+	mustRegister(tools.Tool{
+		Name:        "tail_background_command",
+		Description: "Return the buffered output of a background job. Pass the returned next_seq as since to get only new output",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"id": map[string]interface{}{
+					"type":        "string",
+					"description": "The job id returned by start_background_command",
+				},
+				"since": map[string]interface{}{
+					"type":        "integer",
+					"description": "Only return output from this sequence number on; defaults to the most recent output",
+				},
+			},
+			"required":             []string{"id"},
+			"additionalProperties": false,
+		},
+		Handler: handleTailBackgroundCommand,
+	})
+
+	mustRegister(tools.Tool{
+		Name:        "kill_background_command",
+		Description: "Stop a background job and its child processes: SIGINT, then SIGTERM, then SIGKILL",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"id": map[string]interface{}{
+					"type":        "string",
+					"description": "The job id returned by start_background_command",
+				},
+				"force": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Kill immediately with SIGKILL",
+				},
+			},
+			"required":             []string{"id"},
+			"additionalProperties": false,
+		},
+		Handler: handleKillBackgroundCommand,
+	})
+}
+
+func handleStartBackgroundCommand(call *tools.Call) (*tools.Result, error) {
+	prepared, refusal, err := prepareProjectCommand(call)
+	if err != nil || refusal != nil {
+		return refusal, err
+	}
+	command := prepared.profile.Command
+
+	handle, err := jobExecutor.ExecuteCommandWithOptions(command, prepared.projectDir, prepared.opts)
+	if err != nil {
+		return tools.ErrorResult(fmt.Sprintf("Failed to start %q: %v", command, err)), nil
+	}
+
+	jobsMutex.Lock()
+	pruneExitedJobs()
+	nextJobID++
+	job := &backgroundJob{
+		id:         fmt.Sprintf("job-%d", nextJobID),
+		seq:        nextJobID,
+		profile:    prepared.profile.Name,
+		command:    command,
+		projectDir: prepared.projectDir,
+		handle:     handle,
+		exited:     make(chan struct{}),
+	}
+	jobs[job.id] = job
+	jobsMutex.Unlock()
+
+	// Output is read through the buffer; drain the stream so it can finish
+	go func() {
+		for range handle.Output {
+		}
+		<-handle.Done
+		close(job.exited)
+	}()
+
+	return jsonResult(job.status())
+}
+
+func handleListBackgroundCommands(call *tools.Call) (*tools.Result, error) {
+	jobsMutex.Lock()
+	list := make([]*backgroundJob, 0, len(jobs))
+	for _, job := range jobs {
+		list = append(list, job)
+	}
+	jobsMutex.Unlock()
+
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].seq < list[j].seq
+	})
+	statuses := make([]jobStatus, 0, len(list))
+	for _, job := range list {
+		statuses = append(statuses, job.status())
+	}
+	return jsonResult(statuses)
+}
+
+func handleTailBackgroundCommand(call *tools.Call) (*tools.Result, error) {
+	job, err := lookupJob(call)
+	if err != nil {
+		return nil, err
+	}
+
+	var chunks []types.OutputChunk
+	nextSeq := uint64(0)
+	if since, ok := call.Arguments["since"].(float64); ok && since >= 0 {
+		nextSeq = uint64(since)
+		chunks = job.handle.Chunks.Since(nextSeq)
+	} else {
+		chunks = job.handle.Chunks.Since(0)
+		if len(chunks) > defaultTailChunks {
+			chunks = chunks[len(chunks)-defaultTailChunks:]
+		}
+	}
+	if len(chunks) > 0 {
+		nextSeq = chunks[len(chunks)-1].Seq + 1
+	}
+
+	return jsonResult(map[string]interface{}{
+		"id":       job.id,
+		"running":  job.running(),
+		"next_seq": nextSeq,
+		"output":   executor.PlainText(executor.FormatChunks(chunks)),
+	})
+}
+
+func handleKillBackgroundCommand(call *tools.Call) (*tools.Result, error) {
+	job, err := lookupJob(call)
+	if err != nil {
+		return nil, err
+	}
+
+	if job.running() {
+		jobExecutor.KillProcessTree(job.handle.PID)
+		if force, _ := call.Arguments["force"].(bool); force {
+			jobExecutor.KillProcessTree(job.handle.PID) // A second request sends SIGKILL
+		}
+
+		select {
+		case <-job.exited:
+		case <-time.After(jobKillWait):
+			return tools.ErrorResult(fmt.Sprintf("Job %s is still stopping; check list_background_commands", job.id)), nil
+		}
+	}
+
+	// The final status is returned here, so the job is not kept any longer
+	jobsMutex.Lock()
+	delete(jobs, job.id)
+	jobsMutex.Unlock()
+
+	return jsonResult(job.status())
+}
+
+// pruneExitedJobs drops all but the newest maxExitedJobs exited jobs. The
+// caller holds jobsMutex.
+func pruneExitedJobs() {
+	var exited []*backgroundJob
+	for _, job := range jobs {
+		if !job.running() {
+			exited = append(exited, job)
+		}
+	}
+	if len(exited) <= maxExitedJobs {
+		return
+	}
+
+	sort.Slice(exited, func(i, j int) bool {
+		return exited[i].seq < exited[j].seq
+	})
+	for _, job := range exited[:len(exited)-maxExitedJobs] {
+		delete(jobs, job.id)
+	}
+}
+
+// lookupJob finds the job named by the "id" argument
+func lookupJob(call *tools.Call) (*backgroundJob, error) {
+	id, _ := call.Arguments["id"].(string)
+
+	jobsMutex.Lock()
+	defer jobsMutex.Unlock()
+
+	job, ok := jobs[id]
+	if !ok {
+		return nil, tools.InvalidParams("unknown job %q", id)
+	}
+	return job, nil
+}
+
+// stopBackgroundJobs stops every job when the session ends
+func stopBackgroundJobs() {
+	jobExecutor.Shutdown(jobShutdownTimeout)
+}
+
+func (job *backgroundJob) running() bool {
+	select {
+	case <-job.exited:
+		return false
+	default:
+		return true
+	}
+}
+
+func (job *backgroundJob) status() jobStatus {
+	status := jobStatus{
+		ID:         job.id,
+		Profile:    job.profile,
+		Command:    job.command,
+		ProjectDir: job.projectDir,
+		PID:        job.handle.PID,
+		Running:    job.running(),
+		StartTime:  job.handle.StartTime.Format(time.RFC3339Nano),
+	}
+	if !status.Running {
+		result := job.handle.Result
+		status.ExitCode = &result.ExitCode
+		status.Signal = result.Signal
+		status.DurationMs = result.DurationMs
+	}
+	return status
+}
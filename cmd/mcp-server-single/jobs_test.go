@@ -0,0 +1,67 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"interactive-feedback-mcp/internal/tools"
+	"interactive-feedback-mcp/internal/types"
+)
+
+func TestPruneExitedJobs(t *testing.T) {
+	jobsMutex.Lock()
+	defer jobsMutex.Unlock()
+	saved := jobs
+	defer func() { jobs = saved }()
+
+	jobs = make(map[string]*backgroundJob)
+	running := &backgroundJob{id: "job-0", exited: make(chan struct{})}
+	jobs[running.id] = running
+	for seq := 1; seq <= maxExitedJobs+5; seq++ {
+		job := &backgroundJob{id: fmt.Sprintf("job-%d", seq), seq: seq, exited: make(chan struct{})}
+		close(job.exited)
+		jobs[job.id] = job
+	}
+
+	pruneExitedJobs()
+	assert.Len(t, jobs, maxExitedJobs+1)
+	assert.Contains(t, jobs, "job-0", "running jobs are kept")
+	assert.NotContains(t, jobs, "job-5")
+	assert.Contains(t, jobs, "job-6")
+}
+
+func TestListBackgroundCommands_StartOrder(t *testing.T) {
+	jobsMutex.Lock()
+	saved := jobs
+	jobs = make(map[string]*backgroundJob)
+	// Jobs started within the clock's resolution share a start time
+	start := time.Date(2025, 10, 19, 1, 0, 0, 0, time.UTC)
+	for seq := 1; seq <= 12; seq++ {
+		job := &backgroundJob{
+			id:     fmt.Sprintf("job-%d", seq),
+			seq:    seq,
+			handle: &types.CommandHandle{StartTime: start},
+			exited: make(chan struct{}),
+		}
+		jobs[job.id] = job
+	}
+	jobsMutex.Unlock()
+	defer func() {
+		jobsMutex.Lock()
+		jobs = saved
+		jobsMutex.Unlock()
+	}()
+
+	result, err := handleListBackgroundCommands(&tools.Call{})
+	require.NoError(t, err)
+	var statuses []jobStatus
+	require.NoError(t, json.Unmarshal([]byte(result.Content[0]["text"].(string)), &statuses))
+	require.Len(t, statuses, 12)
+	for i, status := range statuses {
+		assert.Equal(t, fmt.Sprintf("job-%d", i+1), status.ID)
+	}
+}
@@ -3,24 +3,32 @@ package main
 import (
 	"bufio"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"os"
-	"os/exec"
-	"path/filepath"
+	"os/signal"
 	"strings"
+	"sync"
+	"syscall"
 	"time"
 
-	"github.com/google/uuid"
 	"interactive-feedback-mcp/internal/config"
+	"interactive-feedback-mcp/internal/schema"
+	"interactive-feedback-mcp/internal/tools"
 	"interactive-feedback-mcp/internal/types"
+	"interactive-feedback-mcp/internal/workspace"
 )
 
+// MCPRequest is any message read from the client. Responses to requests the
+// server sent carry Result or Error and no Method.
 type MCPRequest struct {
-	JSONRPC string      `json:"jsonrpc"`
-	ID      interface{} `json:"id"`
-	Method  string      `json:"method"`
-	Params  interface{} `json:"params"`
+	JSONRPC string          `json:"jsonrpc"`
+	ID      interface{}     `json:"id,omitempty"`
+	Method  string          `json:"method,omitempty"`
+	Params  interface{}     `json:"params,omitempty"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *MCPError       `json:"error,omitempty"`
 }
 
 type MCPResponse struct {
@@ -31,18 +39,54 @@ type MCPResponse struct {
 }
 
 type MCPError struct {
-	Code    int    `json:"code"`
-	Message string `json:"message"`
+	Code    int         `json:"code"`
+	Message string      `json:"message"`
+	Data    interface{} `json:"data,omitempty"`
 }
 
-type Tool struct {
-	Name        string                 `json:"name"`
-	Description string                 `json:"description"`
-	InputSchema map[string]interface{} `json:"inputSchema"`
+type MCPNotification struct {
+	JSONRPC string      `json:"jsonrpc"`
+	Method  string      `json:"method"`
+	Params  interface{} `json:"params,omitempty"`
 }
 
+// configPollInterval is how often the active project's config file is checked for changes
+const configPollInterval = 2 * time.Second
+
+// maxMessageBytes bounds one JSON-RPC message; base64 attachments make them large
+const maxMessageBytes = 64 * 1024 * 1024
+
+var (
+	toolRegistry = tools.NewRegistry()
+	outputMutex  sync.Mutex
+
+	watcherMutex   sync.Mutex
+	watchedProject string
+	projectWatcher *config.ConfigWatcher
+)
+
 func main() {
+	if len(os.Args) > 1 {
+		os.Exit(runCLI(os.Args[1:]))
+	}
+
+	registerTools()
+	watchProject(".")
+	toolRegistry.SetOnListChanged(func() {
+		sendNotification("notifications/tools/list_changed", nil)
+	})
+
+	// Background jobs must not outlive the session
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-signals
+		stopBackgroundJobs()
+		os.Exit(1)
+	}()
+
 	scanner := bufio.NewScanner(os.Stdin)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxMessageBytes)
 	
 	for scanner.Scan() {
 		line := strings.TrimSpace(scanner.Text())
@@ -56,9 +100,24 @@ func main() {
 			continue
 		}
 
+		if request.Method == "" {
+			handleClientResponse(request)
+			continue
+		}
+
+		if request.ID == nil {
+			handleNotification(request)
+			continue
+		}
+
 		response := handleRequest(request)
 		sendResponse(response)
 	}
+	if err := scanner.Err(); err != nil {
+		log.Printf("failed to read from stdin: %v", err)
+	}
+
+	stopBackgroundJobs()
 }
 
 func handleRequest(request MCPRequest) MCPResponse {
@@ -69,6 +128,10 @@ func handleRequest(request MCPRequest) MCPResponse {
 		return handleToolsList(request)
 	case "tools/call":
 		return handleToolsCall(request)
+	case "prompts/list":
+		return handlePromptsList(request)
+	case "prompts/get":
+		return handlePromptsGet(request)
 	default:
 		return MCPResponse{
 			JSONRPC: "2.0",
@@ -81,7 +144,16 @@ func handleRequest(request MCPRequest) MCPResponse {
 	}
 }
 
+func handleNotification(notification MCPRequest) {
+	switch notification.Method {
+	case "notifications/initialized", "notifications/roots/list_changed":
+		requestRoots()
+	}
+}
+
 func handleInitialize(request MCPRequest) MCPResponse {
+	setClientCapabilities(request.Params)
+
 	return MCPResponse{
 		JSONRPC: "2.0",
 		ID:      request.ID,
@@ -91,6 +163,9 @@ func handleInitialize(request MCPRequest) MCPResponse {
 				"tools": map[string]interface{}{
 					"listChanged": true,
 				},
+				"prompts": map[string]interface{}{
+					"listChanged": false,
+				},
 			},
 			"serverInfo": map[string]string{
 				"name":    "interactive-feedback-mcp",
@@ -101,36 +176,11 @@ func handleInitialize(request MCPRequest) MCPResponse {
 }
 
 func handleToolsList(request MCPRequest) MCPResponse {
-	tools := []Tool{
-		{
-			Name:        "interactive_feedback",
-			Description: "Get interactive feedback from user for development tasks",
-			InputSchema: map[string]interface{}{
-				"type": "object",
-				"properties": map[string]interface{}{
-					"projectDirectory": map[string]interface{}{
-						"type":        "string",
-						"description": "The project directory path",
-					},
-					"prompt": map[string]interface{}{
-						"type":        "string",
-						"description": "The prompt to show to the user",
-					},
-					"previousUserRequest": map[string]interface{}{
-						"type":        "string",
-						"description": "The previous user request that triggered this interactive feedback",
-					},
-				},
-				"required": []string{"projectDirectory", "prompt", "previousUserRequest"},
-			},
-		},
-	}
-
 	return MCPResponse{
 		JSONRPC: "2.0",
 		ID:      request.ID,
 		Result: map[string]interface{}{
-			"tools": tools,
+			"tools": toolRegistry.List(),
 		},
 	}
 }
@@ -152,6 +202,9 @@ func handleToolsCall(request MCPRequest) MCPResponse {
 	var toolCall struct {
 		Name      string                 `json:"name"`
 		Arguments map[string]interface{} `json:"arguments"`
+		Meta      struct {
+			ProgressToken interface{} `json:"progressToken"`
+		} `json:"_meta"`
 	}
 
 	if err := json.Unmarshal(paramsBytes, &toolCall); err != nil {
@@ -165,7 +218,8 @@ func handleToolsCall(request MCPRequest) MCPResponse {
 		}
 	}
 
-	if toolCall.Name != "interactive_feedback" {
+	tool, ok := toolRegistry.Lookup(toolCall.Name)
+	if !ok {
 		return MCPResponse{
 			JSONRPC: "2.0",
 			ID:      request.ID,
@@ -176,144 +230,133 @@ func handleToolsCall(request MCPRequest) MCPResponse {
 		}
 	}
 
-	// Extract arguments
-	projectDir, _ := toolCall.Arguments["projectDirectory"].(string)
-	prompt, _ := toolCall.Arguments["prompt"].(string)
-	previousUserRequest, _ := toolCall.Arguments["previousUserRequest"].(string)
-
-	if projectDir == "" {
-		projectDir = "."
+	if toolCall.Arguments == nil {
+		toolCall.Arguments = map[string]interface{}{}
 	}
 
-	// Run interactive feedback with single popup GUI
-	result := runInteractiveFeedbackWithSinglePopupGUI(projectDir, prompt, previousUserRequest)
-
-	return MCPResponse{
-		JSONRPC: "2.0",
-		ID:      request.ID,
-		Result: map[string]interface{}{
-			"content": []map[string]interface{}{
-				{
-					"type": "text",
-					"text": result,
-				},
+	if fieldErrors := schema.Validate(tool.InputSchema, toolCall.Arguments); len(fieldErrors) > 0 {
+		return MCPResponse{
+			JSONRPC: "2.0",
+			ID:      request.ID,
+			Error: &MCPError{
+				Code:    -32602,
+				Message: "Invalid params: " + schema.Summary(fieldErrors),
+				Data:    fieldErrors,
 			},
-		},
+		}
 	}
-}
 
-func runInteractiveFeedbackWithSinglePopupGUI(projectDir, prompt, previousUserRequest string) string {
-	// Load or create config
-	configManager, err := config.NewConfigManager()
-	if err != nil {
-		return fmt.Sprintf("Error creating config manager: %v", err)
+	call := &tools.Call{
+		Name:      toolCall.Name,
+		Arguments: toolCall.Arguments,
 	}
-	
-	projectConfig := configManager.LoadProjectConfig(projectDir)
-	if projectConfig == nil {
-		projectConfig = &types.ProjectConfig{
-			RunCommand:              "",
-			ExecuteAutomatically:    false,
-			CommandSectionVisible:   true,
-			ConversationHistory:     []types.ConversationEntry{},
+	if token := toolCall.Meta.ProgressToken; token != nil {
+		call.OnProgress = func(progress, total float64, message string) {
+			params := map[string]interface{}{
+				"progressToken": token,
+				"progress":      progress,
+			}
+			if total > 0 {
+				params["total"] = total
+			}
+			if message != "" {
+				params["message"] = message
+			}
+			sendNotification("notifications/progress", params)
 		}
 	}
 
-	// STEP 1: Add previous user request to conversation history FIRST
-	if previousUserRequest != "" {
-		userEntry := types.ConversationEntry{
-			ID:        uuid.New().String(),
-			Timestamp: time.Now(),
-			Role:      "user",
-			Content:   previousUserRequest,
-			IsCurrent: false,
+	result, err := tool.Handler(call)
+	if err != nil {
+		code := -32603
+		var toolError *tools.Error
+		if errors.As(err, &toolError) {
+			code = toolError.Code
+		}
+		return MCPResponse{
+			JSONRPC: "2.0",
+			ID:      request.ID,
+			Error: &MCPError{
+				Code:    code,
+				Message: err.Error(),
+			},
 		}
-		projectConfig.ConversationHistory = append(projectConfig.ConversationHistory, userEntry)
 	}
 
-	// STEP 2: Add agent prompt to conversation history
-	assistantEntry := types.ConversationEntry{
-		ID:        uuid.New().String(),
-		Timestamp: time.Now(),
-		Role:      "assistant",
-		Content:   prompt,
-		IsCurrent: false,
+	return MCPResponse{
+		JSONRPC: "2.0",
+		ID:      request.ID,
+		Result:  result,
 	}
-	projectConfig.ConversationHistory = append(projectConfig.ConversationHistory, assistantEntry)
-
-	// STEP 2.5: Trim conversation history to prevent file bloat (keep last 10 entries)
-	projectConfig.ConversationHistory = trimConversationHistory(projectConfig.ConversationHistory, 10)
-
-	// STEP 3: Auto-add to .gitignore if not already added
-	ensureGitignoreEntry(projectDir)
+}
 
-	// STEP 4: Save config to disk BEFORE calling GUI
-	configManager.SaveProjectConfig(projectDir, projectConfig)
+// resolveProjectDir validates an agent-supplied project directory against the
+// client's workspace roots and the user's allowed roots before anything is
+// written into it. An empty directory defaults to defaultProjectDir.
+func resolveProjectDir(projectDir string) (string, error) {
+	configManager, err := config.NewConfigManager()
+	if err != nil {
+		return "", fmt.Errorf("error creating config manager: %w", err)
+	}
 
-	// Get the directory of the current executable
-	execPath, err := os.Executable()
+	userConfig, err := configManager.LoadUserConfig()
 	if err != nil {
-		return fmt.Sprintf("Error getting executable path: %v", err)
+		return "", err
 	}
-	
-	execDir := filepath.Dir(execPath)
-	
-	// Find the single popup desktop GUI
-	desktopGUI := filepath.Join(execDir, "desktop_gui_single.py")
-	if _, err := os.Stat(desktopGUI); os.IsNotExist(err) {
-		// Try alternative path
-		desktopGUI = filepath.Join(execDir, "..", "desktop_gui_single.py")
-		if _, err := os.Stat(desktopGUI); os.IsNotExist(err) {
-			return "Single popup desktop GUI not found. Please ensure desktop_gui_single.py is in the project directory."
-		}
+
+	roots := currentRoots()
+	if strings.TrimSpace(projectDir) == "" {
+		projectDir = defaultProjectDir()
 	}
 
-	// STEP 4: Launch single popup desktop GUI AFTER saving config
-	cmd := exec.Command("python3", desktopGUI, projectDir, prompt)
-	cmd.Dir = filepath.Dir(desktopGUI)
-	
-	// Capture output
-	output, err := cmd.Output()
+	resolved, err := workspace.Resolve(projectDir, roots, userConfig.AllowedRoots)
 	if err != nil {
-		return fmt.Sprintf("Error running single popup desktop GUI: %v", err)
+		return "", err
 	}
-	
-	userFeedback := strings.TrimSpace(string(output))
-	// Allow empty feedback - user can choose not to provide feedback
-	
-	// STEP 5: Add user feedback to conversation only if feedback is provided
-	if userFeedback != "" {
-		feedbackEntry := types.ConversationEntry{
-			ID:        uuid.New().String(),
-			Timestamp: time.Now(),
-			Role:      "user",
-			Content:   userFeedback,
-			IsCurrent: false,
+
+	if userConfig.StrictRoots {
+		if len(roots) == 0 {
+			return "", fmt.Errorf("strict_roots is enabled but the client did not declare any workspace roots")
+		}
+		if !workspace.Within(resolved, roots) {
+			return "", fmt.Errorf("projectDirectory %s is outside the client's workspace roots", resolved)
 		}
+	}
 
-		projectConfig.ConversationHistory = append(projectConfig.ConversationHistory, feedbackEntry)
+	return resolved, nil
+}
 
-		// Trim conversation history again after adding feedback
-		projectConfig.ConversationHistory = trimConversationHistory(projectConfig.ConversationHistory, 10)
+func mustRegister(tool tools.Tool) {
+	if err := toolRegistry.Register(tool); err != nil {
+		log.Fatalf("Error registering tool: %v", err)
 	}
+}
 
-	// Save updated config with user feedback
-	configManager.SaveProjectConfig(projectDir, projectConfig)
+// watchProject follows the project config of the most recently used project
+// and re-applies its tool settings when the file changes on disk.
+func watchProject(projectDir string) {
+	watcherMutex.Lock()
+	defer watcherMutex.Unlock()
 
-	// Create feedback result
-	feedbackResult := types.FeedbackResult{
-		CommandLogs:         "",
-		InteractiveFeedback: userFeedback,
-		ConversationHistory: projectConfig.ConversationHistory,
+	if projectDir == watchedProject && projectWatcher != nil {
+		return
+	}
+	if projectWatcher != nil {
+		projectWatcher.Stop()
 	}
 
-	// Convert to JSON
-	resultBytes, err := json.MarshalIndent(feedbackResult, "", "  ")
+	configManager, err := config.NewConfigManager()
 	if err != nil {
-		return fmt.Sprintf("Error creating feedback result: %v", err)
+		log.Printf("Error creating config manager: %v", err)
+		return
 	}
 
-	return string(resultBytes)
+	toolRegistry.SetDisabled(configManager.LoadProjectConfig(projectDir).DisabledTools)
+
+	watchedProject = projectDir
+	projectWatcher = configManager.WatchProjectConfig(projectDir, configPollInterval, func(projectConfig *types.ProjectConfig) {
+		toolRegistry.SetDisabled(projectConfig.DisabledTools)
+	})
 }
 
 func sendResponse(response MCPResponse) {
@@ -322,7 +365,27 @@ func sendResponse(response MCPResponse) {
 		log.Printf("Error marshaling response: %v", err)
 		return
 	}
-	fmt.Println(string(responseBytes))
+	writeMessage(responseBytes)
+}
+
+func sendNotification(method string, params interface{}) {
+	notificationBytes, err := json.Marshal(MCPNotification{
+		JSONRPC: "2.0",
+		Method:  method,
+		Params:  params,
+	})
+	if err != nil {
+		log.Printf("Error marshaling notification: %v", err)
+		return
+	}
+	writeMessage(notificationBytes)
+}
+
+// writeMessage serializes writes to stdout, which is shared with notification goroutines
+func writeMessage(message []byte) {
+	outputMutex.Lock()
+	defer outputMutex.Unlock()
+	fmt.Println(string(message))
 }
 
 func sendError(id interface{}, code int, message, data string) {
@@ -334,44 +397,8 @@ func sendError(id interface{}, code int, message, data string) {
 			Message: message,
 		},
 	}
-	sendResponse(response)
-}
-
-func trimConversationHistory(history []types.ConversationEntry, maxEntries int) []types.ConversationEntry {
-	if len(history) <= maxEntries {
-		return history
-	}
-	
-	// Keep the last maxEntries entries
-	startIndex := len(history) - maxEntries
-	return history[startIndex:]
-}
-
-func ensureGitignoreEntry(projectDir string) {
-	gitignorePath := filepath.Join(projectDir, ".gitignore")
-	configFileName := ".interactive-feedback-config.json"
-	
-	// Check if .gitignore exists
-	if _, err := os.Stat(gitignorePath); os.IsNotExist(err) {
-		// Create .gitignore if it doesn't exist
-		content := fmt.Sprintf("# Interactive Feedback MCP Configuration\n%s\n", configFileName)
-		os.WriteFile(gitignorePath, []byte(content), 0644)
-		return
+	if data != "" {
+		response.Error.Data = data
 	}
-	
-	// Read existing .gitignore
-	content, err := os.ReadFile(gitignorePath)
-	if err != nil {
-		return // Skip if can't read
-	}
-	
-	// Check if already contains our entry
-	contentStr := string(content)
-	if strings.Contains(contentStr, configFileName) {
-		return // Already added
-	}
-	
-	// Add our entry to .gitignore
-	entry := fmt.Sprintf("\n# Interactive Feedback MCP Configuration\n%s\n", configFileName)
-	os.WriteFile(gitignorePath, []byte(contentStr+entry), 0644)
+	sendResponse(response)
 }
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"interactive-feedback-mcp/internal/config"
+	"interactive-feedback-mcp/internal/types"
+)
+
+func TestResolveProjectDir(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+	manager, err := config.NewConfigManager()
+	require.NoError(t, err)
+
+	// Without roots a missing directory is the server's working directory
+	workingDir, err := os.Getwd()
+	require.NoError(t, err)
+	workingDir, err = filepath.EvalSymlinks(workingDir)
+	require.NoError(t, err)
+	resolved, err := resolveProjectDir("")
+	require.NoError(t, err)
+	assert.Equal(t, workingDir, resolved)
+
+	_, err = resolveProjectDir("relative/dir")
+	assert.ErrorContains(t, err, "must be an absolute path")
+
+	project, err := filepath.EvalSymlinks(t.TempDir())
+	require.NoError(t, err)
+	resolved, err = resolveProjectDir(project)
+	require.NoError(t, err)
+	assert.Equal(t, project, resolved)
+
+	require.NoError(t, manager.SaveUserConfig(&types.UserConfig{AllowedRoots: []string{t.TempDir()}}))
+	_, err = resolveProjectDir(project)
+	assert.ErrorContains(t, err, "outside the allowed workspace roots")
+
+	require.NoError(t, manager.SaveUserConfig(&types.UserConfig{StrictRoots: true}))
+	_, err = resolveProjectDir(project)
+	assert.ErrorContains(t, err, "did not declare any workspace roots")
+}
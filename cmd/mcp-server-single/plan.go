@@ -0,0 +1,123 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"interactive-feedback-mcp/internal/config"
+	"interactive-feedback-mcp/internal/plan"
+	"interactive-feedback-mcp/internal/tools"
+	"interactive-feedback-mcp/internal/types"
+)
+
+// planRequest is the file handed to the GUI's plan editor
+type planRequest struct {
+	ProjectDirectory string   `json:"project_directory"`
+	Title            string   `json:"title"`
+	Steps            []string `json:"steps"`
+}
+
+func registerPlanTool() {
+	mustRegister(tools.Tool{
+		Name:        "approve_plan",
+		Description: "Show the user an ordered plan to approve. The user can reorder, edit, delete or add steps and uncheck steps they do not approve; the edited plan is returned as structured data",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"projectDirectory": projectDirectoryProperty(),
+				"title": map[string]interface{}{
+					"type":        "string",
+					"description": "What the plan is for, shown above the steps",
+				},
+				"steps": map[string]interface{}{
+					"type":        "array",
+					"items":       map[string]interface{}{"type": "string"},
+					"description": "The steps of the plan, in order",
+				},
+			},
+			"required":             []string{"steps"},
+			"additionalProperties": false,
+		},
+		Handler: handleApprovePlan,
+	})
+}
+
+func handleApprovePlan(call *tools.Call) (*tools.Result, error) {
+	projectDir, _ := call.Arguments["projectDirectory"].(string)
+	title, _ := call.Arguments["title"].(string)
+	var steps []string
+	if values, ok := call.Arguments["steps"].([]interface{}); ok {
+		for _, value := range values {
+			if step := strings.TrimSpace(value.(string)); step != "" {
+				steps = append(steps, step)
+			}
+		}
+	}
+	if len(steps) == 0 {
+		return nil, tools.InvalidParams("steps must contain at least one step")
+	}
+
+	projectDir, err := resolveProjectDir(projectDir)
+	if err != nil {
+		return nil, tools.InvalidParams("%v", err)
+	}
+	watchProject(projectDir)
+
+	desktopGUI, err := findDesktopGUI()
+	if err != nil {
+		return nil, err
+	}
+
+	output, err := runGUIWithRequest(desktopGUI, "--plan", planRequest{
+		ProjectDirectory: projectDir,
+		Title:            title,
+		Steps:            steps,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := plan.Cancelled(title, steps)
+	if output != nil {
+		var decisions plan.Decisions
+		if err := json.Unmarshal(output, &decisions); err != nil {
+			return nil, fmt.Errorf("failed to read plan from the GUI: %w", err)
+		}
+		if result, err = plan.Reconcile(title, steps, decisions); err != nil {
+			return nil, fmt.Errorf("invalid plan from the GUI: %w", err)
+		}
+	}
+
+	// The whole exchange is one structured history entry
+	recordConversationEntry(projectDir, types.ConversationEntry{
+		ID:        uuid.New().String(),
+		Timestamp: time.Now(),
+		Role:      "user",
+		Content:   plan.Summary(result),
+		Kind:      types.EntryKindPlan,
+		Payload:   &result,
+	})
+
+	return jsonResult(result)
+}
+
+// recordConversationEntry appends an entry to the project's conversation history
+func recordConversationEntry(projectDir string, entry types.ConversationEntry) {
+	configManager, err := config.NewConfigManager()
+	if err != nil {
+		log.Printf("Error creating config manager: %v", err)
+		return
+	}
+
+	projectConfig := configManager.LoadProjectConfig(projectDir)
+	projectConfig.ConversationHistory = trimConversationHistory(append(projectConfig.ConversationHistory, entry), 10)
+
+	ensureGitignoreEntry(configManager, projectDir)
+	if err := configManager.SaveProjectConfig(projectDir, projectConfig); err != nil {
+		log.Printf("Error saving conversation history: %v", err)
+	}
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"interactive-feedback-mcp/internal/config"
+	"interactive-feedback-mcp/internal/executor"
+	"interactive-feedback-mcp/internal/tools"
+	"interactive-feedback-mcp/internal/types"
+)
+
+// defaultCommandTimeout stops run_project_command when command_limits sets no
+// timeout. The server handles one request at a time, so a hanging command
+// would otherwise block every later request.
+const defaultCommandTimeout = 10 * time.Minute
+
+func registerProjectCommandTool() {
+	mustRegister(tools.Tool{
+		Name:        "run_project_command",
+		Description: "Run one of the project's configured commands (e.g. its tests) and return the result. The user approves each command the first time it runs. Output is streamed as progress notifications when the request has a progress token. Commands are stopped after command_limits.timeout_seconds, or 10 minutes; use start_background_command for servers and watchers",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"projectDirectory": projectDirectoryProperty(),
+				"profile": map[string]interface{}{
+					"type":        "string",
+					"description": "Name of the command profile to run; defaults to the project's default command",
+				},
+			},
+			"additionalProperties": false,
+		},
+		Handler: handleRunProjectCommand,
+	})
+}
+
+// preparedCommand is an approved command profile ready to start
+type preparedCommand struct {
+	projectDir string
+	profile    types.CommandProfile
+	opts       executor.Options
+}
+
+// prepareProjectCommand resolves the profile named in the tool arguments and
+// makes sure the user has approved it. A non-nil result reports a refusal to
+// return to the agent as is.
+func prepareProjectCommand(call *tools.Call) (*preparedCommand, *tools.Result, error) {
+	projectDir, _ := call.Arguments["projectDirectory"].(string)
+	profileName, _ := call.Arguments["profile"].(string)
+
+	projectDir, err := resolveProjectDir(projectDir)
+	if err != nil {
+		return nil, nil, tools.InvalidParams("%v", err)
+	}
+	watchProject(projectDir)
+
+	configManager, err := config.NewConfigManager()
+	if err != nil {
+		return nil, nil, fmt.Errorf("error creating config manager: %w", err)
+	}
+	projectConfig := configManager.LoadProjectConfig(projectDir)
+
+	profile, err := lookupCommandProfile(projectConfig, profileName)
+	if err != nil {
+		return nil, nil, tools.InvalidParams("%v", err)
+	}
+
+	userConfig, err := configManager.LoadUserConfig()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Agents may only run commands the user has approved for this project,
+	// with the same shell, working directory and environment
+	opts := executor.OptionsForProfile(projectConfig, profile)
+	spec := executor.LaunchSpec(profile.Command, projectDir, opts)
+	approved, err := configManager.HasCommandApproval(projectDir, spec)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !approved {
+		desktopGUI, err := findDesktopGUI()
+		if err != nil {
+			return nil, nil, err
+		}
+		if !confirmCommand(desktopGUI, projectDir, spec) {
+			return nil, tools.ErrorResult(fmt.Sprintf("The user did not approve running %q.", profile.Command)), nil
+		}
+		if err := configManager.ApproveCommand(projectDir, spec); err != nil {
+			log.Printf("Failed to save command approval: %v", err)
+		}
+	}
+
+	return &preparedCommand{
+		projectDir: projectDir,
+		profile:    profile,
+		opts:       opts.WithLimits(userConfig.CommandLimits),
+	}, nil, nil
+}
+
+func handleRunProjectCommand(call *tools.Call) (*tools.Result, error) {
+	prepared, refusal, err := prepareProjectCommand(call)
+	if err != nil || refusal != nil {
+		return refusal, err
+	}
+	command := prepared.profile.Command
+	if prepared.opts.Timeout == 0 {
+		prepared.opts.Timeout = defaultCommandTimeout
+	}
+
+	handle, err := executor.NewCommandExecutor().ExecuteCommandWithOptions(command, prepared.projectDir, prepared.opts)
+	if err != nil {
+		return tools.ErrorResult(fmt.Sprintf("Failed to start %q: %v", command, err)), nil
+	}
+
+	chunks := 0
+	for output := range handle.Output {
+		chunks++
+		call.ReportProgress(float64(chunks), 0, executor.PlainText(output))
+	}
+	<-handle.Done
+
+	result := *handle.Result
+	result.Output = executor.PlainText(result.Output)
+	return jsonResult(result)
+}
+
+// jsonResult returns a value as indented JSON text
+func jsonResult(value interface{}) (*tools.Result, error) {
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode result: %w", err)
+	}
+	return tools.TextResult(string(data)), nil
+}
+
+// lookupCommandProfile finds a profile by name, or the default profile when
+// no name is given
+func lookupCommandProfile(projectConfig *types.ProjectConfig, name string) (types.CommandProfile, error) {
+	if name == "" {
+		profile, ok := config.DefaultCommandProfile(projectConfig)
+		if !ok {
+			return types.CommandProfile{}, fmt.Errorf("the project has no run_command or commands configured")
+		}
+		return profile, nil
+	}
+
+	profile, ok := config.FindCommandProfile(projectConfig, name)
+	if !ok {
+		return types.CommandProfile{}, fmt.Errorf("unknown command profile %q (available: %s)", name, strings.Join(config.CommandProfileNames(projectConfig), ", "))
+	}
+	return profile, nil
+}
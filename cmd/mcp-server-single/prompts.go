@@ -0,0 +1,169 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"interactive-feedback-mcp/internal/config"
+	"interactive-feedback-mcp/internal/prompts"
+)
+
+// projectDirectoryArgument is declared by every prompt; it picks the
+// project whose templates are used, resolved as the tools resolve it
+var projectDirectoryArgument = prompts.Argument{
+	Name:        "projectDirectory",
+	Description: "The project directory; defaults to the first workspace root, or the server's working directory",
+}
+
+func handlePromptsList(request MCPRequest) MCPResponse {
+	// The list has no arguments, so it shows the default project's templates
+	var templates []prompts.Template
+	projectDir, err := resolveProjectDir("")
+	if err == nil {
+		templates, err = loadPromptTemplates(projectDir)
+	}
+	if err != nil {
+		return MCPResponse{
+			JSONRPC: "2.0",
+			ID:      request.ID,
+			Error: &MCPError{
+				Code:    -32603,
+				Message: err.Error(),
+			},
+		}
+	}
+
+	promptList := make([]map[string]interface{}, 0, len(templates))
+	for _, tmpl := range templates {
+		arguments := append([]prompts.Argument{}, tmpl.Arguments...)
+		if !hasArgument(tmpl, projectDirectoryArgument.Name) {
+			arguments = append(arguments, projectDirectoryArgument)
+		}
+		promptList = append(promptList, map[string]interface{}{
+			"name":        tmpl.Name,
+			"description": tmpl.Description,
+			"arguments":   arguments,
+		})
+	}
+
+	return MCPResponse{
+		JSONRPC: "2.0",
+		ID:      request.ID,
+		Result: map[string]interface{}{
+			"prompts": promptList,
+		},
+	}
+}
+
+func handlePromptsGet(request MCPRequest) MCPResponse {
+	paramsBytes, err := json.Marshal(request.Params)
+	if err != nil {
+		return MCPResponse{
+			JSONRPC: "2.0",
+			ID:      request.ID,
+			Error: &MCPError{
+				Code:    -32602,
+				Message: "Invalid params",
+			},
+		}
+	}
+
+	var promptGet struct {
+		Name      string            `json:"name"`
+		Arguments map[string]string `json:"arguments"`
+	}
+
+	if err := json.Unmarshal(paramsBytes, &promptGet); err != nil {
+		return MCPResponse{
+			JSONRPC: "2.0",
+			ID:      request.ID,
+			Error: &MCPError{
+				Code:    -32602,
+				Message: "Invalid params",
+			},
+		}
+	}
+
+	projectDir, err := resolveProjectDir(promptGet.Arguments[projectDirectoryArgument.Name])
+	if err != nil {
+		return MCPResponse{
+			JSONRPC: "2.0",
+			ID:      request.ID,
+			Error: &MCPError{
+				Code:    -32602,
+				Message: err.Error(),
+			},
+		}
+	}
+
+	templates, err := loadPromptTemplates(projectDir)
+	if err != nil {
+		return MCPResponse{
+			JSONRPC: "2.0",
+			ID:      request.ID,
+			Error: &MCPError{
+				Code:    -32603,
+				Message: err.Error(),
+			},
+		}
+	}
+
+	tmpl, ok := prompts.Find(templates, promptGet.Name)
+	if !ok {
+		return MCPResponse{
+			JSONRPC: "2.0",
+			ID:      request.ID,
+			Error: &MCPError{
+				Code:    -32602,
+				Message: fmt.Sprintf("Unknown prompt: %s", promptGet.Name),
+			},
+		}
+	}
+
+	text, err := prompts.Render(tmpl, promptGet.Arguments)
+	if err != nil {
+		return MCPResponse{
+			JSONRPC: "2.0",
+			ID:      request.ID,
+			Error: &MCPError{
+				Code:    -32602,
+				Message: err.Error(),
+			},
+		}
+	}
+
+	return MCPResponse{
+		JSONRPC: "2.0",
+		ID:      request.ID,
+		Result: map[string]interface{}{
+			"description": tmpl.Description,
+			"messages": []map[string]interface{}{
+				{
+					"role": "user",
+					"content": map[string]interface{}{
+						"type": "text",
+						"text": text,
+					},
+				},
+			},
+		},
+	}
+}
+
+// loadPromptTemplates merges builtin, user and project templates
+func loadPromptTemplates(projectDir string) ([]prompts.Template, error) {
+	configManager, err := config.NewConfigManager()
+	if err != nil {
+		return nil, fmt.Errorf("error creating config manager: %w", err)
+	}
+	return prompts.Load(configManager.UserConfigDir(), projectDir)
+}
+
+func hasArgument(tmpl prompts.Template, name string) bool {
+	for _, arg := range tmpl.Arguments {
+		if arg.Name == name {
+			return true
+		}
+	}
+	return false
+}
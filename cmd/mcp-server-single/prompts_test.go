@@ -0,0 +1,66 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"interactive-feedback-mcp/internal/prompts"
+)
+
+// promptResult decodes a prompts response the way a client sees it
+func promptResult(t *testing.T, response MCPResponse, result interface{}) {
+	t.Helper()
+	require.Nil(t, response.Error)
+	data, err := json.Marshal(response.Result)
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(data, result))
+}
+
+func TestHandlePrompts(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+	projectDir := t.TempDir()
+	projectFile := `{"prompts": [{"name": "release", "text": "Release {{version}}?", "arguments": [{"name": "version", "required": true}]}]}`
+	require.NoError(t, os.WriteFile(filepath.Join(projectDir, prompts.ProjectFileName), []byte(projectFile), 0644))
+
+	var list struct {
+		Prompts []struct {
+			Name      string             `json:"name"`
+			Arguments []prompts.Argument `json:"arguments"`
+		} `json:"prompts"`
+	}
+	promptResult(t, handlePromptsList(MCPRequest{ID: 1, Method: "prompts/list"}), &list)
+	require.NotEmpty(t, list.Prompts)
+	for _, prompt := range list.Prompts {
+		assert.Contains(t, prompt.Arguments, projectDirectoryArgument, prompt.Name)
+	}
+
+	get := func(arguments map[string]string) MCPResponse {
+		return handlePromptsGet(MCPRequest{ID: 2, Method: "prompts/get", Params: map[string]interface{}{"name": "release", "arguments": arguments}})
+	}
+
+	// The project's templates are found through the declared argument
+	var prompt struct {
+		Messages []struct {
+			Content struct {
+				Text string `json:"text"`
+			} `json:"content"`
+		} `json:"messages"`
+	}
+	promptResult(t, get(map[string]string{"projectDirectory": projectDir, "version": "1.2"}), &prompt)
+	require.Len(t, prompt.Messages, 1)
+	assert.Equal(t, "Release 1.2?", prompt.Messages[0].Content.Text)
+
+	response := get(map[string]string{"version": "1.2"})
+	require.NotNil(t, response.Error)
+	assert.Equal(t, "Unknown prompt: release", response.Error.Message)
+
+	response = get(map[string]string{"projectDirectory": "relative", "version": "1.2"})
+	require.NotNil(t, response.Error)
+	assert.Equal(t, -32602, response.Error.Code)
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"interactive-feedback-mcp/internal/diff"
+	"interactive-feedback-mcp/internal/tools"
+	"interactive-feedback-mcp/internal/types"
+)
+
+// reviewRequest is the file handed to the GUI's review window
+type reviewRequest struct {
+	ProjectDirectory string      `json:"project_directory"`
+	Prompt           string      `json:"prompt"`
+	Files            []diff.File `json:"files"`
+}
+
+func registerReviewTool() {
+	mustRegister(tools.Tool{
+		Name:        "request_diff_review",
+		Description: "Ask the user to review a change hunk by hunk. Each hunk can be approved, rejected or commented on; the result lists approved and rejected hunks with the user's comments",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"projectDirectory": projectDirectoryProperty(),
+				"diff": map[string]interface{}{
+					"type":        "string",
+					"description": "The change as a unified diff. Defaults to the uncommitted changes to tracked files (git diff HEAD) in projectDirectory",
+				},
+				"prompt": map[string]interface{}{
+					"type":        "string",
+					"description": "What the change does, shown above the diff",
+				},
+			},
+			"additionalProperties": false,
+		},
+		Handler: handleRequestDiffReview,
+	})
+}
+
+func handleRequestDiffReview(call *tools.Call) (*tools.Result, error) {
+	projectDir, _ := call.Arguments["projectDirectory"].(string)
+	diffText, _ := call.Arguments["diff"].(string)
+	prompt, _ := call.Arguments["prompt"].(string)
+
+	projectDir, err := resolveProjectDir(projectDir)
+	if err != nil {
+		return nil, tools.InvalidParams("%v", err)
+	}
+	watchProject(projectDir)
+
+	if strings.TrimSpace(diffText) == "" {
+		if diffText, err = diff.GitDiff(projectDir); err != nil {
+			return nil, tools.InvalidParams("no diff was given and none could be computed: %v", err)
+		}
+	}
+
+	files, err := diff.Parse(diffText)
+	if err != nil {
+		return nil, tools.InvalidParams("invalid diff: %v", err)
+	}
+	hunks := 0
+	for _, file := range files {
+		hunks += len(file.Hunks)
+	}
+	if hunks == 0 {
+		return tools.ErrorResult("There are no changes to review."), nil
+	}
+
+	desktopGUI, err := findDesktopGUI()
+	if err != nil {
+		return nil, err
+	}
+
+	decisions, err := runReviewGUI(desktopGUI, reviewRequest{
+		ProjectDirectory: projectDir,
+		Prompt:           prompt,
+		Files:            files,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if decisions == nil {
+		return jsonResult(types.DiffReviewResult{
+			Verdict:       types.VerdictCancelled,
+			ApprovedHunks: []types.HunkReview{},
+			RejectedHunks: []types.HunkReview{},
+		})
+	}
+
+	result, err := diff.Summarize(files, *decisions)
+	if err != nil {
+		return nil, fmt.Errorf("invalid review from the GUI: %w", err)
+	}
+	return jsonResult(result)
+}
+
+// runReviewGUI shows the review window and returns the user's decisions, or
+// nil if the window was closed without submitting
+func runReviewGUI(desktopGUI string, request reviewRequest) (*diff.Decisions, error) {
+	output, err := runGUIWithRequest(desktopGUI, "--review", request)
+	if err != nil || output == nil {
+		return nil, err
+	}
+
+	var decisions diff.Decisions
+	if err := json.Unmarshal(output, &decisions); err != nil {
+		return nil, fmt.Errorf("failed to read review from the GUI: %w", err)
+	}
+	return &decisions, nil
+}
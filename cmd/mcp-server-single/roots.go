@@ -0,0 +1,174 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+var (
+	rootsMutex          sync.RWMutex
+	clientSupportsRoots bool
+	workspaceRoots      []string
+
+	pendingMutex    sync.Mutex
+	pendingRequests = make(map[string]func(MCPRequest))
+	nextRequestID   int
+)
+
+// setClientCapabilities records what the client announced in initialize
+func setClientCapabilities(params interface{}) {
+	paramsBytes, err := json.Marshal(params)
+	if err != nil {
+		return
+	}
+
+	var initialize struct {
+		Capabilities struct {
+			Roots *struct {
+				ListChanged bool `json:"listChanged"`
+			} `json:"roots"`
+		} `json:"capabilities"`
+	}
+	if err := json.Unmarshal(paramsBytes, &initialize); err != nil {
+		return
+	}
+
+	rootsMutex.Lock()
+	clientSupportsRoots = initialize.Capabilities.Roots != nil
+	rootsMutex.Unlock()
+}
+
+// requestRoots asks the client for its workspace folders via roots/list
+func requestRoots() {
+	rootsMutex.RLock()
+	supported := clientSupportsRoots
+	rootsMutex.RUnlock()
+	if !supported {
+		return
+	}
+
+	sendRequest("roots/list", nil, func(response MCPRequest) {
+		if response.Error != nil {
+			log.Printf("roots/list failed: %s", response.Error.Message)
+			return
+		}
+
+		var result struct {
+			Roots []struct {
+				URI  string `json:"uri"`
+				Name string `json:"name"`
+			} `json:"roots"`
+		}
+		if err := json.Unmarshal(response.Result, &result); err != nil {
+			log.Printf("Invalid roots/list result: %v", err)
+			return
+		}
+
+		roots := make([]string, 0, len(result.Roots))
+		for _, root := range result.Roots {
+			path, err := rootPath(root.URI)
+			if err != nil {
+				log.Printf("Ignoring root %s: %v", root.URI, err)
+				continue
+			}
+			roots = append(roots, path)
+		}
+
+		rootsMutex.Lock()
+		workspaceRoots = roots
+		rootsMutex.Unlock()
+
+		if len(roots) > 0 {
+			watchProject(roots[0])
+		}
+	})
+}
+
+// currentRoots returns the workspace roots last reported by the client
+func currentRoots() []string {
+	rootsMutex.RLock()
+	defer rootsMutex.RUnlock()
+	return append([]string(nil), workspaceRoots...)
+}
+
+// defaultProjectDir is used when the agent does not name a project: the
+// first workspace root, or the server's working directory if the client
+// declared no roots
+func defaultProjectDir() string {
+	if roots := currentRoots(); len(roots) > 0 {
+		return roots[0]
+	}
+	if dir, err := os.Getwd(); err == nil {
+		return dir
+	}
+	return "."
+}
+
+// projectDirectoryProperty is the input schema of the projectDirectory
+// argument shared by the tools, which resolve it with resolveProjectDir
+func projectDirectoryProperty() map[string]interface{} {
+	return map[string]interface{}{
+		"type":        "string",
+		"description": "The project directory path. Relative paths are resolved against the client's workspace roots; defaults to the first root, or the server's working directory when the client declares no roots",
+	}
+}
+
+// rootPath converts a file:// root URI into a local path
+func rootPath(uri string) (string, error) {
+	parsed, err := url.Parse(uri)
+	if err != nil {
+		return "", err
+	}
+	if parsed.Scheme != "file" {
+		return "", fmt.Errorf("unsupported scheme %q", parsed.Scheme)
+	}
+
+	path := parsed.Path
+	if runtime.GOOS == "windows" {
+		// file:///C:/work -> C:/work
+		path = strings.TrimPrefix(path, "/")
+	}
+	return filepath.Clean(filepath.FromSlash(path)), nil
+}
+
+// sendRequest sends a server-to-client request and registers the handler for its response
+func sendRequest(method string, params interface{}, onResponse func(MCPRequest)) {
+	pendingMutex.Lock()
+	nextRequestID++
+	id := fmt.Sprintf("server-%d", nextRequestID)
+	pendingRequests[id] = onResponse
+	pendingMutex.Unlock()
+
+	requestBytes, err := json.Marshal(MCPRequest{
+		JSONRPC: "2.0",
+		ID:      id,
+		Method:  method,
+		Params:  params,
+	})
+	if err != nil {
+		log.Printf("Error marshaling request: %v", err)
+		return
+	}
+	writeMessage(requestBytes)
+}
+
+// handleClientResponse dispatches a response to a request the server sent
+func handleClientResponse(response MCPRequest) {
+	id := fmt.Sprint(response.ID)
+
+	pendingMutex.Lock()
+	onResponse, exists := pendingRequests[id]
+	delete(pendingRequests, id)
+	pendingMutex.Unlock()
+
+	if exists {
+		onResponse(response)
+	}
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRootPath(t *testing.T) {
+	tests := map[string]struct {
+		uri      string
+		expected string
+		windows  string
+	}{
+		"plain":          {"file:///home/me/work", "/home/me/work", `home\me\work`},
+		"escaped":        {"file:///home/me/my%20project", "/home/me/my project", `home\me\my project`},
+		"trailing slash": {"file:///home/me/work/", "/home/me/work", `home\me\work`},
+		"drive letter":   {"file:///C:/work", "/C:/work", `C:\work`},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			path, err := rootPath(test.uri)
+			require.NoError(t, err)
+			if runtime.GOOS == "windows" {
+				assert.Equal(t, test.windows, path)
+			} else {
+				assert.Equal(t, test.expected, path)
+			}
+		})
+	}
+
+	_, err := rootPath("https://example.com/work")
+	assert.ErrorContains(t, err, `unsupported scheme "https"`)
+	_, err = rootPath("file://%zz")
+	assert.Error(t, err)
+}
+
+// captureMessages runs fn and returns the JSON-RPC messages it wrote to stdout
+func captureMessages(t *testing.T, fn func()) []MCPRequest {
+	t.Helper()
+	reader, writer, err := os.Pipe()
+	require.NoError(t, err)
+	stdout := os.Stdout
+	os.Stdout = writer
+	fn()
+	os.Stdout = stdout
+	require.NoError(t, writer.Close())
+
+	data, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	var messages []MCPRequest
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		if line == "" {
+			continue
+		}
+		var message MCPRequest
+		require.NoError(t, json.Unmarshal([]byte(line), &message))
+		messages = append(messages, message)
+	}
+	return messages
+}
+
+func TestHandleClientResponse(t *testing.T) {
+	var received []MCPRequest
+	messages := captureMessages(t, func() {
+		sendRequest("ping", nil, func(response MCPRequest) {
+			received = append(received, response)
+		})
+	})
+	require.Len(t, messages, 1)
+	assert.Equal(t, "ping", messages[0].Method)
+	id, ok := messages[0].ID.(string)
+	require.True(t, ok)
+
+	// Responses to unknown ids are ignored
+	handleClientResponse(MCPRequest{JSONRPC: "2.0", ID: "server-unknown", Result: json.RawMessage(`{}`)})
+	assert.Empty(t, received)
+
+	handleClientResponse(MCPRequest{JSONRPC: "2.0", ID: id, Result: json.RawMessage(`{"ok":true}`)})
+	require.Len(t, received, 1)
+	assert.JSONEq(t, `{"ok":true}`, string(received[0].Result))
+
+	// A handler runs once, even if the client repeats the response
+	handleClientResponse(MCPRequest{JSONRPC: "2.0", ID: id, Result: json.RawMessage(`{}`)})
+	assert.Len(t, received, 1)
+}
+
+func TestRequestRoots(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("builds root URIs from POSIX paths")
+	}
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+	first, second := t.TempDir(), t.TempDir()
+	defer func() {
+		rootsMutex.Lock()
+		clientSupportsRoots, workspaceRoots = false, nil
+		rootsMutex.Unlock()
+	}()
+
+	// Clients without the roots capability are not asked
+	setClientCapabilities(map[string]interface{}{"capabilities": map[string]interface{}{}})
+	assert.Empty(t, captureMessages(t, requestRoots))
+
+	setClientCapabilities(map[string]interface{}{"capabilities": map[string]interface{}{"roots": map[string]interface{}{}}})
+	messages := captureMessages(t, requestRoots)
+	require.Len(t, messages, 1)
+	assert.Equal(t, "roots/list", messages[0].Method)
+
+	result, err := json.Marshal(map[string]interface{}{
+		"roots": []map[string]string{
+			{"uri": "file://" + first},
+			{"uri": "https://example.com/ignored"},
+			{"uri": "file://" + second},
+		},
+	})
+	require.NoError(t, err)
+	handleClientResponse(MCPRequest{JSONRPC: "2.0", ID: messages[0].ID, Result: result})
+
+	assert.Equal(t, []string{first, second}, currentRoots())
+	assert.Equal(t, first, defaultProjectDir())
+}
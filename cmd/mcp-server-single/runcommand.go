@@ -0,0 +1,234 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"interactive-feedback-mcp/internal/config"
+	"interactive-feedback-mcp/internal/executor"
+	"interactive-feedback-mcp/internal/types"
+)
+
+// Run command modes for the interactive_feedback tool
+const (
+	runCommandOff      = "off"
+	runCommandBefore   = "before"
+	runCommandParallel = "parallel"
+)
+
+// commandRun tracks a project command started during a feedback round-trip
+type commandRun struct {
+	profile  string
+	command  string
+	executor *executor.CommandExecutor
+	handle   *types.CommandHandle
+	waitOnce sync.Once
+	result   *types.CommandResult
+	err      error
+}
+
+// selectCommandProfiles picks the run mode and the command profiles to run.
+// Profiles named in the tool arguments run in parallel unless a mode is
+// given; a mode alone runs the default profile; with neither, the profiles
+// marked auto_run run in parallel.
+func selectCommandProfiles(requested string, names []string, projectConfig *types.ProjectConfig) (string, []types.CommandProfile, error) {
+	if len(names) > 0 {
+		var profiles []types.CommandProfile
+		for _, name := range names {
+			profile, err := lookupCommandProfile(projectConfig, name)
+			if err != nil {
+				return "", nil, err
+			}
+			profiles = append(profiles, profile)
+		}
+		if requested == "" {
+			requested = runCommandParallel
+		}
+		if requested == runCommandOff {
+			return runCommandOff, nil, nil
+		}
+		return requested, profiles, nil
+	}
+
+	if requested != "" {
+		profile, ok := config.DefaultCommandProfile(projectConfig)
+		if !ok || requested == runCommandOff {
+			return runCommandOff, nil, nil
+		}
+		return requested, []types.CommandProfile{profile}, nil
+	}
+
+	var profiles []types.CommandProfile
+	for _, profile := range config.CommandProfiles(projectConfig) {
+		if profile.AutoRun {
+			profiles = append(profiles, profile)
+		}
+	}
+	if len(profiles) == 0 {
+		return runCommandOff, nil, nil
+	}
+	return runCommandParallel, profiles, nil
+}
+
+// startApprovedCommand starts a command profile with the user's limits.
+// Whether the agent picked it or the project marks it auto_run, it only runs
+// once the user has approved it, whatever require_command_approval says; a
+// new or changed command is shown for approval first.
+func startApprovedCommand(configManager *config.ConfigManager, desktopGUI, projectDir string, projectConfig *types.ProjectConfig, profile types.CommandProfile) *commandRun {
+	command := profile.Command
+
+	userConfig, err := configManager.LoadUserConfig()
+	if err != nil {
+		return &commandRun{profile: profile.Name, command: command, err: err}
+	}
+
+	opts := executor.OptionsForProfile(projectConfig, profile)
+	spec := executor.LaunchSpec(command, projectDir, opts)
+	approved, err := configManager.HasCommandApproval(projectDir, spec)
+	if err != nil {
+		return &commandRun{profile: profile.Name, command: command, err: err}
+	}
+	if !approved {
+		if !confirmCommand(desktopGUI, projectDir, spec) {
+			return &commandRun{profile: profile.Name, command: command, err: fmt.Errorf("not approved by the user")}
+		}
+		if err := configManager.ApproveCommand(projectDir, spec); err != nil {
+			log.Printf("Failed to save command approval: %v", err)
+		}
+	}
+
+	opts = opts.WithLimits(userConfig.CommandLimits)
+	run := startProjectCommand(command, projectDir, opts)
+	run.profile = profile.Name
+	return run
+}
+
+// confirmCommand asks the user whether a command may run, showing its launch
+// spec in the GUI's confirm mode
+func confirmCommand(desktopGUI, projectDir, spec string) bool {
+	cmd := exec.Command("python3", desktopGUI, "--confirm", projectDir, spec)
+	cmd.Dir = filepath.Dir(desktopGUI)
+
+	output, err := cmd.Output()
+	if err != nil {
+		log.Printf("Command confirmation failed: %v", err)
+		return false
+	}
+	return strings.TrimSpace(string(output)) == "approved"
+}
+
+// startProjectCommand launches the command; its output is captured by the executor
+func startProjectCommand(command, projectDir string, opts executor.Options) *commandRun {
+	run := &commandRun{
+		command:  command,
+		executor: executor.NewCommandExecutor(),
+	}
+
+	handle, err := run.executor.ExecuteCommandWithOptions(command, projectDir, opts)
+	if err != nil {
+		run.err = err
+		return run
+	}
+	run.handle = handle
+
+	// The result carries the output; drain the stream so the executor can finish delivering it
+	go func() {
+		for range handle.Output {
+		}
+	}()
+
+	return run
+}
+
+// wait blocks until the command has finished
+func (run *commandRun) wait() {
+	if run.handle == nil {
+		return
+	}
+	run.waitOnce.Do(func() {
+		<-run.handle.Done
+		run.result = run.handle.Result
+	})
+}
+
+// stop kills a command whose result is no longer needed
+func (run *commandRun) stop() {
+	if run.handle == nil {
+		return
+	}
+	run.executor.KillProcessTree(run.handle.PID)
+	run.wait()
+}
+
+// logs formats the captured output for FeedbackResult.CommandLogs
+func (run *commandRun) logs() string {
+	var logs strings.Builder
+	fmt.Fprintf(&logs, "$ %s\n", run.command)
+
+	if run.result == nil {
+		fmt.Fprintf(&logs, "[failed to run command: %v]\n", run.err)
+		return logs.String()
+	}
+
+	// Terminal output carries colors and redraws that only make sense on a screen
+	logs.WriteString(executor.PlainText(run.result.Output))
+	if run.result.Truncated {
+		fmt.Fprintf(&logs, "[output truncated: %d bytes captured in total]\n", run.result.BytesCaptured)
+	}
+
+	duration := (time.Duration(run.result.DurationMs) * time.Millisecond).String()
+	switch {
+	case run.result.TimedOut:
+		fmt.Fprintf(&logs, "[timed out, stopped with %s after %s]\n", run.result.TerminatedBy, duration)
+	case run.result.TerminatedBy != "":
+		fmt.Fprintf(&logs, "[stopped with %s after %s]\n", run.result.TerminatedBy, duration)
+	case run.result.Signal != "":
+		fmt.Fprintf(&logs, "[terminated by %s after %s]\n", run.result.Signal, duration)
+	case run.result.Error != "":
+		fmt.Fprintf(&logs, "[failed: %s after %s]\n", run.result.Error, duration)
+	default:
+		fmt.Fprintf(&logs, "[exit code %d after %s]\n", run.result.ExitCode, duration)
+	}
+	return logs.String()
+}
+
+// summarizeRuns fills in the command fields of a feedback result. The exit
+// code is the first non-zero one, and the duration the longest, since
+// profiles run side by side.
+func summarizeRuns(feedbackResult *types.FeedbackResult, runs []*commandRun) {
+	var logs []string
+	for _, run := range runs {
+		run.wait()
+		logs = append(logs, run.logs())
+
+		summary := types.CommandRunSummary{Name: run.profile, Command: run.command}
+		if run.result == nil {
+			summary.Error = fmt.Sprint(run.err)
+		} else {
+			exitCode := run.result.ExitCode
+			summary.ExitCode = &exitCode
+			summary.DurationMs = run.result.DurationMs
+
+			if feedbackResult.CommandExitCode == nil || *feedbackResult.CommandExitCode == 0 {
+				feedbackResult.CommandExitCode = &exitCode
+			}
+			if run.result.DurationMs > feedbackResult.CommandDurationMs {
+				feedbackResult.CommandDurationMs = run.result.DurationMs
+			}
+		}
+		feedbackResult.CommandRuns = append(feedbackResult.CommandRuns, summary)
+	}
+	feedbackResult.CommandLogs = strings.Join(logs, "\n")
+
+	if len(runs) == 1 {
+		if runs[0].result != nil {
+			feedbackResult.CommandEnvironment = runs[0].result.Environment
+		}
+		feedbackResult.CommandRuns = nil // Nothing to add to the fields above
+	}
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"interactive-feedback-mcp/internal/config"
+	"interactive-feedback-mcp/internal/types"
+)
+
+func TestSelectCommandProfiles(t *testing.T) {
+	projectConfig := &types.ProjectConfig{
+		Commands: []types.CommandProfile{
+			{Name: "test", Command: "go test ./...", Default: true},
+			{Name: "lint", Command: "golangci-lint run", AutoRun: true},
+			{Name: "vet", Command: "go vet ./...", AutoRun: true},
+		},
+	}
+	names := func(profiles []types.CommandProfile) []string {
+		var found []string
+		for _, profile := range profiles {
+			found = append(found, profile.Name)
+		}
+		return found
+	}
+
+	// Named profiles run in parallel unless a mode is given
+	mode, profiles, err := selectCommandProfiles("", []string{"lint", "test"}, projectConfig)
+	require.NoError(t, err)
+	assert.Equal(t, runCommandParallel, mode)
+	assert.Equal(t, []string{"lint", "test"}, names(profiles))
+
+	mode, profiles, err = selectCommandProfiles(runCommandBefore, []string{"test"}, projectConfig)
+	require.NoError(t, err)
+	assert.Equal(t, runCommandBefore, mode)
+	assert.Equal(t, []string{"test"}, names(profiles))
+
+	mode, profiles, err = selectCommandProfiles(runCommandOff, []string{"test"}, projectConfig)
+	require.NoError(t, err)
+	assert.Equal(t, runCommandOff, mode)
+	assert.Empty(t, profiles)
+
+	_, _, err = selectCommandProfiles("", []string{"deploy"}, projectConfig)
+	assert.ErrorContains(t, err, `unknown command profile "deploy"`)
+
+	// A mode alone runs the default profile
+	mode, profiles, err = selectCommandProfiles(runCommandBefore, nil, projectConfig)
+	require.NoError(t, err)
+	assert.Equal(t, runCommandBefore, mode)
+	assert.Equal(t, []string{"test"}, names(profiles))
+
+	// With neither, the auto_run profiles run
+	mode, profiles, err = selectCommandProfiles("", nil, projectConfig)
+	require.NoError(t, err)
+	assert.Equal(t, runCommandParallel, mode)
+	assert.Equal(t, []string{"lint", "vet"}, names(profiles))
+
+	mode, profiles, err = selectCommandProfiles("", nil, &types.ProjectConfig{RunCommand: "make"})
+	require.NoError(t, err)
+	assert.Equal(t, runCommandOff, mode)
+	assert.Empty(t, profiles)
+}
+
+// fakeConfirmGUI writes a desktop GUI that answers every confirmation with
+// answer and records the launch specs it was shown
+func fakeConfirmGUI(t *testing.T, answer string) (string, func() []string) {
+	if _, err := exec.LookPath("python3"); err != nil {
+		t.Skip("python3 is not installed")
+	}
+	dir := t.TempDir()
+	asked := filepath.Join(dir, "asked")
+	script := fmt.Sprintf("import sys\nopen(%q, 'a').write(sys.argv[3] + '\\0')\nprint(%q)\n", asked, answer)
+	desktopGUI := filepath.Join(dir, "desktop_gui_single.py")
+	require.NoError(t, os.WriteFile(desktopGUI, []byte(script), 0644))
+
+	return desktopGUI, func() []string {
+		data, err := os.ReadFile(asked)
+		if os.IsNotExist(err) {
+			return nil
+		}
+		require.NoError(t, err)
+		return strings.Split(strings.TrimSuffix(string(data), "\x00"), "\x00")
+	}
+}
+
+func TestStartApprovedCommand(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses POSIX shell commands")
+	}
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+	manager, err := config.NewConfigManager()
+	require.NoError(t, err)
+
+	projectDir, err := filepath.EvalSymlinks(t.TempDir())
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(projectDir, ".env"), []byte("TOKEN=secret\n"), 0600))
+	projectConfig := &types.ProjectConfig{EnvFile: ".env"}
+	profile := types.CommandProfile{Name: "touch", Command: "touch ran", AutoRun: true}
+	marker := filepath.Join(projectDir, "ran")
+
+	// Without require_command_approval a new command is still shown first,
+	// and a refusal keeps it from running
+	denyGUI, denyAsked := fakeConfirmGUI(t, "denied")
+	run := startApprovedCommand(manager, denyGUI, projectDir, projectConfig, profile)
+	assert.ErrorContains(t, run.err, "not approved")
+	assert.Nil(t, run.handle)
+	assert.NoFileExists(t, marker)
+	require.Len(t, denyAsked(), 1)
+	assert.Contains(t, denyAsked()[0], "touch ran\nenv file: .env (sha256 ")
+	assert.NotContains(t, denyAsked()[0], "secret")
+
+	approveGUI, approveAsked := fakeConfirmGUI(t, "approved")
+	run = startApprovedCommand(manager, approveGUI, projectDir, projectConfig, profile)
+	require.NoError(t, run.err)
+	run.wait()
+	assert.Equal(t, 0, run.result.ExitCode)
+	assert.FileExists(t, marker)
+	assert.Len(t, approveAsked(), 1)
+
+	// Once approved it runs without asking
+	require.NoError(t, os.Remove(marker))
+	run = startApprovedCommand(manager, denyGUI, projectDir, projectConfig, profile)
+	require.NoError(t, run.err)
+	run.wait()
+	assert.FileExists(t, marker)
+	assert.Len(t, denyAsked(), 1)
+
+	// A changed env file needs approving again
+	require.NoError(t, os.Remove(marker))
+	require.NoError(t, os.WriteFile(filepath.Join(projectDir, ".env"), []byte("BASH_ENV=./x.sh\n"), 0600))
+	run = startApprovedCommand(manager, denyGUI, projectDir, projectConfig, profile)
+	assert.ErrorContains(t, run.err, "not approved")
+	assert.NoFileExists(t, marker)
+	assert.Len(t, denyAsked(), 2)
+}
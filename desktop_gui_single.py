@@ -10,13 +10,120 @@ import subprocess
 import tempfile
 import json
 import tkinter as tk
-from tkinter import ttk, scrolledtext
+from tkinter import ttk, scrolledtext, filedialog
 from pathlib import Path
+from urllib.parse import urlparse, unquote
+
+try:  # Optional: dropping files onto the window
+    from tkinterdnd2 import TkinterDnD, DND_FILES
+except ImportError:
+    TkinterDnD = None
+
+try:  # Optional: pasting images from the clipboard, showing JPEG and WebP
+    from PIL import Image, ImageGrab, ImageTk
+except ImportError:
+    Image = ImageGrab = ImageTk = None
+
+def format_size(size):
+    """Render a byte count for people"""
+    if size < 1024:
+        return f"{size} B"
+    if size < 1024 * 1024:
+        return f"{size / 1024:.1f} KB"
+    return f"{size / (1024 * 1024):.1f} MB"
+
+def entry_text(entry):
+    """Render a conversation entry as plain text according to its kind"""
+    kind = entry.get('kind') or 'text'
+    payload = entry.get('payload') or {}
+    content = entry.get('content', '')
+    if kind == 'choice':
+        text = f"{payload['question']}\n" if payload.get('question') else ''
+        text += "Selected: " + ", ".join(payload.get('selected') or [])
+        if payload.get('options'):
+            text += " (options: " + ", ".join(payload['options']) + ")"
+        return text
+    if kind == 'form':
+        lines = [payload['title']] if payload.get('title') else []
+        lines += [f"{f.get('label') or f.get('name')}: {f.get('value', '')}" for f in payload.get('fields') or []]
+        return "\n".join(lines)
+    if kind == 'command_result':
+        lines = [f"$ {payload.get('command', '')}"]
+        output = (payload.get('output') or '').rstrip("\n")
+        if output:
+            lines.append(output)
+        if payload.get('error'):
+            lines.append(f"failed to run: {payload['error']}")
+        else:
+            if payload.get('timed_out'):
+                status = "timed out"
+            elif payload.get('signal'):
+                status = f"killed by {payload['signal']}"
+            elif payload.get('exit_code') is not None:
+                status = f"exit code {payload['exit_code']}"
+            else:
+                status = "finished"
+            if payload.get('duration_ms'):
+                status += f" in {payload['duration_ms'] / 1000:.1f}s"
+            lines.append(status)
+        return "\n".join(lines)
+    if kind == 'attachment':
+        lines = [content] if content else []
+        lines += [f"Attached: {a.get('name')} ({a.get('mime_type')}, {format_size(a.get('size', 0))})"
+                  for a in payload.get('attachments') or []]
+        return "\n".join(lines)
+    if kind == 'system':
+        return content or payload.get('event', '')
+    # Text, plans (whose content is their summary) and kinds this GUI does not know
+    return content
+
+def file_uri_paths(text):
+    """Paths of the file:// URIs in text, or [] unless every line is one"""
+    lines = [line.strip() for line in text.splitlines() if line.strip()]
+    if not lines or not all(line.startswith('file://') for line in lines):
+        return []
+    return [unquote(urlparse(line).path) for line in lines]
+
+def load_prompt_attachments(path):
+    """The attachments the server wrote for the prompt, or [] without any"""
+    if not path:
+        return []
+    try:
+        with open(path, 'r', encoding='utf-8') as f:
+            return json.load(f)
+    except (OSError, ValueError) as e:
+        print(f"Failed to read prompt attachments: {e}", file=sys.stderr)
+        return []
+
+def load_image(path, max_side):
+    """A Tk image of the file no larger than max_side, or None if Tk cannot show it"""
+    if ImageTk is not None:
+        try:
+            image = Image.open(path)
+            image.thumbnail((max_side, max_side))
+            return ImageTk.PhotoImage(image)
+        except Exception:
+            return None
+    try:
+        image = tk.PhotoImage(file=path)  # PNG and GIF without Pillow
+    except tk.TclError:
+        return None
+    factor = -(-max(image.width(), image.height()) // max_side)
+    return image.subsample(factor) if factor > 1 else image
 
 class SinglePopupDesktopGUI:
-    def __init__(self, project_directory, prompt):
+    def __init__(self, project_directory, prompt, config_file=None, attachments_file=None,
+                 prompt_attachments_file=None):
         self.project_directory = project_directory
         self.prompt = prompt
+        # The server passes the config location, which may be outside the project
+        self.config_file = config_file or os.path.join(project_directory, '.interactive-feedback-config.json')
+        # The server reads the attached paths from here; without it attaching is disabled
+        self.attachments_file = attachments_file
+        self.attachments = []
+        # Images, files and snippets the agent sent with the prompt
+        self.prompt_attachments = load_prompt_attachments(prompt_attachments_file)
+        self.prompt_images = []  # Tk drops images that are not referenced
         self.feedback = None
         self.root = None
         
@@ -111,7 +218,10 @@ class SinglePopupDesktopGUI:
         conversation_text = self.get_conversation_history()
         
         # Create the main window
-        self.root = tk.Tk()
+        if TkinterDnD is not None and self.attachments_file:
+            self.root = TkinterDnD.Tk()
+        else:
+            self.root = tk.Tk()
         self.root.title("Interactive Feedback MCP")
         
         # Set maximized window by default (keeps title bar with close/minimize buttons)
@@ -167,11 +277,12 @@ class SinglePopupDesktopGUI:
 {conversation_text}
 
 Current Prompt: {self.prompt}
-
-Please provide your feedback below:"""
+"""
         
         info_text.config(state=tk.NORMAL)
         info_text.insert(tk.END, info_content)
+        self.show_prompt_attachments(info_text)
+        info_text.insert(tk.END, "\nPlease provide your feedback below:")
         info_text.config(state=tk.DISABLED)
         
         # Feedback input area
@@ -188,6 +299,15 @@ Please provide your feedback below:"""
                                                                               **self.text_style)
         feedback_entry_frame.grid(row=1, column=0, sticky=(tk.W, tk.E), pady=(0, 10))
         
+        # Attached files, added with the button, by pasting or by dropping
+        self.attachments_label = ttk.Label(feedback_frame, text="")
+        if self.attachments_file:
+            self.attachments_label.grid(row=2, column=0, sticky=tk.W, pady=(0, 5))
+            self.feedback_entry.bind('<<Paste>>', self.paste_attachments)
+            if TkinterDnD is not None:
+                self.root.drop_target_register(DND_FILES)
+                self.root.dnd_bind('<<Drop>>', self.drop_attachments)
+        
         # Buttons frame
         buttons_frame = ttk.Frame(main_frame)
         buttons_frame.grid(row=3, column=0, sticky=(tk.W, tk.E))
@@ -205,7 +325,22 @@ Please provide your feedback below:"""
         # Cancel button
         cancel_btn = ttk.Button(buttons_frame, text="Cancel", 
                                command=self.cancel_feedback)
-        cancel_btn.grid(row=0, column=2)
+        cancel_btn.grid(row=0, column=2, padx=(0, 10))
+        
+        # Attachment buttons
+        if self.attachments_file:
+            attach_btn = ttk.Button(buttons_frame, text="Attach Files…",
+                                   command=self.choose_attachments)
+            attach_btn.grid(row=0, column=3, padx=(0, 10))
+            clear_btn = ttk.Button(buttons_frame, text="Clear Attachments",
+                                  command=self.clear_attachments)
+            clear_btn.grid(row=0, column=4, padx=(0, 10))
+        
+        # Save Conversation button, when the server exported the conversation
+        if self.conversation_export('.md') is not None:
+            save_btn = ttk.Button(buttons_frame, text="Save Conversation…",
+                                 command=self.save_conversation)
+            save_btn.grid(row=0, column=5)
         
         # Focus on feedback entry
         self.feedback_entry.focus()
@@ -259,12 +394,106 @@ Please provide your feedback below:"""
         except Exception as e:
             self.show_notification("Error", f"Failed to copy conversation: {str(e)}")
     
+    def show_prompt_attachments(self, text):
+        """Insert the agent's images, files and snippets after the prompt"""
+        text.tag_configure('attachment', foreground='#9cdcfe')
+        text.tag_configure('snippet', background='#1e1e1e', lmargin1=12, lmargin2=12)
+        for attachment in self.prompt_attachments:
+            label = f"{attachment.get('mime_type', '')}, {format_size(attachment.get('size', 0))}"
+            text.insert(tk.END, f"\n📎 {attachment.get('name', '')} ({label})\n", 'attachment')
+            if attachment.get('type') == 'image':
+                image = load_image(attachment.get('path', ''), 600)
+                if image is None:
+                    text.insert(tk.END, "(this image cannot be shown without Pillow)\n")
+                    continue
+                self.prompt_images.append(image)
+                text.image_create(tk.END, image=image)
+                text.insert(tk.END, "\n")
+            elif attachment.get('type') == 'text':
+                if attachment.get('language'):
+                    text.insert(tk.END, f"{attachment['language']}\n", 'attachment')
+                text.insert(tk.END, attachment.get('text', '').rstrip('\n') + "\n", 'snippet')
+    
     def submit_feedback(self):
         """Submit feedback and close dialog"""
         self.feedback = self.feedback_entry.get("1.0", tk.END).strip()
+        self.write_attachments()
         self.root.quit()
         self.root.destroy()
     
+    def add_attachments(self, paths):
+        """Attach files, ignoring ones already attached"""
+        for path in paths:
+            path = os.path.abspath(path)
+            if os.path.isfile(path) and path not in self.attachments:
+                self.attachments.append(path)
+        self.update_attachments_label()
+    
+    def clear_attachments(self):
+        """Remove all attachments"""
+        self.attachments = []
+        self.update_attachments_label()
+    
+    def update_attachments_label(self):
+        """Show the names and total size of the attached files"""
+        if not self.attachments:
+            self.attachments_label.config(text="")
+            return
+        total = sum(os.path.getsize(path) for path in self.attachments if os.path.exists(path))
+        names = ", ".join(os.path.basename(path) for path in self.attachments)
+        self.attachments_label.config(text=f"📎 {names} ({format_size(total)})")
+    
+    def choose_attachments(self):
+        """Pick files to attach"""
+        paths = filedialog.askopenfilenames(parent=self.root, title="Attach Files",
+                                            initialdir=self.project_directory)
+        self.add_attachments(self.root.tk.splitlist(paths) if paths else [])
+    
+    def paste_attachments(self, event=None):
+        """Attach pasted images or file URIs; other pastes insert text as usual"""
+        if ImageGrab is not None:
+            try:
+                clipboard = ImageGrab.grabclipboard()
+            except Exception:
+                clipboard = None
+            if isinstance(clipboard, list):
+                self.add_attachments(clipboard)
+                return "break"
+            if clipboard is not None:
+                self.add_attachments([self.save_pasted_image(clipboard)])
+                return "break"
+        try:
+            paths = file_uri_paths(self.root.clipboard_get())
+        except tk.TclError:
+            paths = []
+        if paths:
+            self.add_attachments(paths)
+            return "break"
+        return None
+    
+    def save_pasted_image(self, image):
+        """Save a pasted image next to the attachments file"""
+        directory = os.path.dirname(self.attachments_file)
+        number = sum(1 for path in self.attachments if os.path.dirname(path) == directory) + 1
+        path = os.path.join(directory, f"pasted-{number}.png")
+        image.save(path, "PNG")
+        return path
+    
+    def drop_attachments(self, event):
+        """Attach files dropped onto the window"""
+        self.add_attachments(self.root.tk.splitlist(event.data))
+        return event.action
+    
+    def write_attachments(self):
+        """Hand the attached paths to the server"""
+        if not self.attachments_file or not self.attachments:
+            return
+        try:
+            with open(self.attachments_file, 'w', encoding='utf-8') as f:
+                json.dump(self.attachments, f)
+        except OSError as e:
+            print(f"Failed to write attachments: {e}", file=sys.stderr)
+    
     def cancel_feedback(self):
         """Cancel and close dialog without feedback"""
         self.feedback = ""
@@ -274,7 +503,7 @@ Please provide your feedback below:"""
     def get_conversation_history(self):
         """Get conversation history from config file"""
         try:
-            config_file = os.path.join(self.project_directory, '.interactive-feedback-config.json')
+            config_file = self.config_file
             if os.path.exists(config_file):
                 with open(config_file, 'r') as f:
                     config = json.load(f)
@@ -287,9 +516,9 @@ Please provide your feedback below:"""
                         
                         for entry in reversed(history):
                             if entry.get('role') == 'user' and last_user is None:
-                                last_user = entry.get('content', '')
+                                last_user = entry_text(entry)
                             elif entry.get('role') == 'assistant' and last_assistant is None:
-                                last_assistant = entry.get('content', '')
+                                last_assistant = entry_text(entry)
                             
                             if last_user and last_assistant:
                                 break
@@ -305,7 +534,7 @@ agent: {last_assistant}
                         last_user = None
                         for entry in reversed(history):
                             if entry.get('role') == 'user':
-                                last_user = entry.get('content', '')
+                                last_user = entry_text(entry)
                                 break
                         
                         if last_user:
@@ -319,10 +548,45 @@ user: {last_user}
         except Exception as e:
             return "Previous Conversation: Error loading conversation history."
     
+    def conversation_export(self, extension):
+        """The server's export of the conversation (.md, .json or .txt), or None"""
+        if not self.attachments_file:
+            return None
+        path = os.path.join(os.path.dirname(self.attachments_file), 'conversation' + extension)
+        try:
+            with open(path, 'r', encoding='utf-8') as f:
+                return f.read()
+        except OSError:
+            return None
+    
+    def save_conversation(self):
+        """Save the conversation as Markdown, JSON or text, by file extension"""
+        path = filedialog.asksaveasfilename(
+            parent=self.root, title="Save Conversation",
+            initialdir=self.project_directory, initialfile="conversation.md",
+            defaultextension=".md",
+            filetypes=[("Markdown", "*.md"), ("JSON", "*.json"), ("Text", "*.txt")])
+        if not path:
+            return
+        extension = os.path.splitext(path)[1].lower()
+        text = self.conversation_export(extension if extension in ('.md', '.json', '.txt') else '.md')
+        if text is None:
+            self.show_notification("Error", "No conversation export is available")
+            return
+        try:
+            with open(path, 'w', encoding='utf-8') as f:
+                f.write(text)
+        except OSError as e:
+            self.show_notification("Error", f"Failed to save conversation: {e}")
+    
     def get_conversation_text_for_copy(self):
         """Get conversation text formatted for copying"""
+        # The server's Markdown export matches what the other interfaces copy
+        exported = self.conversation_export('.md')
+        if exported:
+            return exported
         try:
-            config_file = os.path.join(self.project_directory, '.interactive-feedback-config.json')
+            config_file = self.config_file
             if os.path.exists(config_file):
                 with open(config_file, 'r') as f:
                     config = json.load(f)
@@ -335,9 +599,9 @@ user: {last_user}
                         
                         for entry in reversed(history):
                             if entry.get('role') == 'user' and last_user is None:
-                                last_user = entry.get('content', '')
+                                last_user = entry_text(entry)
                             elif entry.get('role') == 'assistant' and last_assistant is None:
-                                last_assistant = entry.get('content', '')
+                                last_assistant = entry_text(entry)
                             
                             if last_user and last_assistant:
                                 break
@@ -349,7 +613,7 @@ user: {last_user}
                         last_user = None
                         for entry in reversed(history):
                             if entry.get('role') == 'user':
-                                last_user = entry.get('content', '')
+                                last_user = entry_text(entry)
                                 break
                         
                         if last_user:
@@ -414,16 +678,372 @@ user: {last_user}
         except (EOFError, KeyboardInterrupt):
             return ""  # Return empty string on interrupt
 
+class DiffReviewGUI(SinglePopupDesktopGUI):
+    """Review window for request_diff_review: approve, reject or comment on each hunk"""
+
+    VERDICTS = ('approved', 'rejected', 'pending')
+
+    def __init__(self, request):
+        super().__init__(request.get('project_directory', ''), request.get('prompt', ''))
+        # One entry per hunk, in diff order
+        self.hunks = []
+        for file in request.get('files', []):
+            path = file.get('new_path') or file.get('old_path') or ''
+            for index, hunk in enumerate(file.get('hunks', [])):
+                self.hunks.append({'file': path, 'hunk': index, 'header': hunk.get('header', ''),
+                                   'lines': hunk.get('lines', []),
+                                   'verdict': 'pending', 'comment': ''})
+        self.current = None
+        self.result = None
+
+    def create_review_dialog(self):
+        """Show the files and hunks and return the decisions, or None if cancelled"""
+        self.root = tk.Tk()
+        self.root.title("Interactive Feedback MCP - Review changes")
+        self.root.geometry("1200x800")
+        self.apply_dark_theme()
+        ttk.Style().configure('TRadiobutton', background="#2b2b2b", foreground="#ffffff")
+        self.root.attributes('-topmost', True)
+        self.root.after_idle(lambda: self.root.attributes('-topmost', False))
+
+        main_frame = ttk.Frame(self.root, padding="10")
+        main_frame.grid(row=0, column=0, sticky=(tk.W, tk.E, tk.N, tk.S))
+        self.root.columnconfigure(0, weight=1)
+        self.root.rowconfigure(0, weight=1)
+        main_frame.columnconfigure(1, weight=1)
+        main_frame.rowconfigure(1, weight=1)
+
+        title = self.prompt or f"Review changes in {self.project_directory}"
+        ttk.Label(main_frame, text=title, wraplength=1100,
+                  font=('Arial', 12, 'bold')).grid(row=0, column=0, columnspan=2, pady=(0, 10), sticky=tk.W)
+
+        # Hunk list
+        list_frame, self.hunk_list = self.create_rounded_widget(
+            main_frame, tk.Listbox, width=45, exportselection=False, activestyle='none',
+            bg=self.text_style['bg'], fg=self.text_style['fg'],
+            selectbackground=self.text_style['selectbackground'], relief='flat', highlightthickness=0)
+        list_frame.grid(row=1, column=0, rowspan=2, padx=(0, 10), sticky=(tk.N, tk.S, tk.W, tk.E))
+        self.hunk_list.bind('<<ListboxSelect>>', self.on_select_hunk)
+
+        # Hunk text, colored like a terminal diff
+        diff_frame, self.diff_text = self.create_rounded_widget(
+            main_frame, scrolledtext.ScrolledText, wrap=tk.NONE, state=tk.DISABLED, **self.text_style)
+        diff_frame.grid(row=1, column=1, sticky=(tk.W, tk.E, tk.N, tk.S))
+        self.diff_text.tag_configure('added', foreground='#7ee787')
+        self.diff_text.tag_configure('removed', foreground='#ff7b72')
+        self.diff_text.tag_configure('header', foreground='#79c0ff')
+
+        # Decision and comment for the selected hunk
+        decision_frame = ttk.Frame(main_frame)
+        decision_frame.grid(row=2, column=1, pady=(10, 0), sticky=(tk.W, tk.E))
+        decision_frame.columnconfigure(0, weight=1)
+        self.verdict = tk.StringVar(value='pending')
+        buttons = ttk.Frame(decision_frame)
+        buttons.grid(row=0, column=0, sticky=tk.W)
+        for column, (label, verdict) in enumerate((("Approve", 'approved'), ("Reject", 'rejected'), ("Undecided", 'pending'))):
+            ttk.Radiobutton(buttons, text=label, value=verdict, variable=self.verdict,
+                            command=self.save_current).grid(row=0, column=column, padx=(0, 10))
+        ttk.Label(decision_frame, text="Comment on this hunk:").grid(row=1, column=0, sticky=tk.W, pady=(5, 5))
+        comment_frame, self.hunk_comment = self.create_rounded_widget(
+            decision_frame, tk.Text, height=3, wrap=tk.WORD, **self.text_style)
+        comment_frame.grid(row=2, column=0, sticky=(tk.W, tk.E))
+
+        # Overall comment and actions
+        ttk.Label(main_frame, text="Overall comment:").grid(row=3, column=0, columnspan=2, sticky=tk.W, pady=(10, 5))
+        overall_frame, self.overall_comment = self.create_rounded_widget(
+            main_frame, tk.Text, height=3, wrap=tk.WORD, **self.text_style)
+        overall_frame.grid(row=4, column=0, columnspan=2, sticky=(tk.W, tk.E), pady=(0, 10))
+
+        actions = ttk.Frame(main_frame)
+        actions.grid(row=5, column=0, columnspan=2, sticky=tk.W)
+        ttk.Button(actions, text="Approve All", command=lambda: self.set_all('approved')).grid(row=0, column=0, padx=(0, 10))
+        ttk.Button(actions, text="Reject All", command=lambda: self.set_all('rejected')).grid(row=0, column=1, padx=(0, 10))
+        ttk.Button(actions, text="Submit Review", command=self.submit_review).grid(row=0, column=2, padx=(0, 10))
+        ttk.Button(actions, text="Cancel", command=self.cancel_feedback).grid(row=0, column=3)
+
+        self.refresh_list()
+        if self.hunks:
+            self.hunk_list.selection_set(0)
+            self.show_hunk(0)
+
+        self.root.protocol("WM_DELETE_WINDOW", self.cancel_feedback)
+        self.root.mainloop()
+        return self.result
+
+    def hunk_label(self, hunk):
+        marks = {'approved': '✓', 'rejected': '✗', 'pending': '·'}
+        note = ' ✎' if hunk['comment'] else ''
+        return f"{marks[hunk['verdict']]} {hunk['file']} #{hunk['hunk'] + 1}{note}"
+
+    def refresh_list(self):
+        selection = self.hunk_list.curselection()
+        self.hunk_list.delete(0, tk.END)
+        for hunk in self.hunks:
+            self.hunk_list.insert(tk.END, self.hunk_label(hunk))
+        for index in selection:
+            self.hunk_list.selection_set(index)
+
+    def on_select_hunk(self, event=None):
+        selection = self.hunk_list.curselection()
+        if selection and selection[0] != self.current:
+            self.save_current()
+            self.show_hunk(selection[0])
+
+    def show_hunk(self, index):
+        self.current = index
+        hunk = self.hunks[index]
+        self.diff_text.config(state=tk.NORMAL)
+        self.diff_text.delete("1.0", tk.END)
+        self.diff_text.insert(tk.END, f"{hunk['file']}\n{hunk['header']}\n", 'header')
+        for line in hunk['lines']:
+            tag = 'added' if line.startswith('+') else 'removed' if line.startswith('-') else ''
+            self.diff_text.insert(tk.END, line + "\n", tag)
+        self.diff_text.config(state=tk.DISABLED)
+        self.verdict.set(hunk['verdict'])
+        self.hunk_comment.delete("1.0", tk.END)
+        self.hunk_comment.insert(tk.END, hunk['comment'])
+
+    def save_current(self):
+        if self.current is None:
+            return
+        hunk = self.hunks[self.current]
+        hunk['verdict'] = self.verdict.get()
+        hunk['comment'] = self.hunk_comment.get("1.0", tk.END).strip()
+        self.refresh_list()
+
+    def set_all(self, verdict):
+        self.save_current()
+        for hunk in self.hunks:
+            hunk['verdict'] = verdict
+        self.verdict.set(verdict)
+        self.refresh_list()
+
+    def submit_review(self):
+        self.save_current()
+        self.result = {
+            'hunks': [{'file': h['file'], 'hunk': h['hunk'], 'header': h['header'],
+                       'verdict': h['verdict'], 'comment': h['comment']} for h in self.hunks],
+            'comment': self.overall_comment.get("1.0", tk.END).strip(),
+        }
+        self.root.quit()
+        self.root.destroy()
+
+    def cancel_feedback(self):
+        self.result = None
+        self.root.quit()
+        self.root.destroy()
+
+class PlanEditorGUI(SinglePopupDesktopGUI):
+    """Plan editor for approve_plan: reorder, edit, delete, add and check steps"""
+
+    def __init__(self, request):
+        super().__init__(request.get('project_directory', ''), request.get('title', ''))
+        self.proposed = list(request.get('steps', []))
+        # original is the index of the proposed step, or None for added steps
+        self.steps = [{'text': text, 'original': index, 'approved': True}
+                      for index, text in enumerate(self.proposed)]
+        self.result = None
+
+    def create_plan_dialog(self):
+        """Show the plan editor and return the decisions, or None if cancelled"""
+        self.root = tk.Tk()
+        self.root.title("Interactive Feedback MCP - Approve plan")
+        self.root.geometry("900x650")
+        self.apply_dark_theme()
+        self.root.attributes('-topmost', True)
+        self.root.after_idle(lambda: self.root.attributes('-topmost', False))
+
+        main_frame = ttk.Frame(self.root, padding="10")
+        main_frame.grid(row=0, column=0, sticky=(tk.W, tk.E, tk.N, tk.S))
+        self.root.columnconfigure(0, weight=1)
+        self.root.rowconfigure(0, weight=1)
+        main_frame.columnconfigure(0, weight=1)
+        main_frame.rowconfigure(1, weight=1)
+
+        ttk.Label(main_frame, text=self.prompt or "Review the plan", wraplength=850,
+                  font=('Arial', 12, 'bold')).grid(row=0, column=0, columnspan=2, pady=(0, 10), sticky=tk.W)
+
+        list_frame, self.step_list = self.create_rounded_widget(
+            main_frame, tk.Listbox, exportselection=False, activestyle='none',
+            bg=self.text_style['bg'], fg=self.text_style['fg'], font=('Arial', 11),
+            selectbackground=self.text_style['selectbackground'], relief='flat', highlightthickness=0)
+        list_frame.grid(row=1, column=0, sticky=(tk.W, tk.E, tk.N, tk.S))
+        self.step_list.bind('<<ListboxSelect>>', self.on_select_step)
+        self.step_list.bind('<Double-Button-1>', lambda event: self.toggle_step())
+
+        # Step actions
+        side = ttk.Frame(main_frame)
+        side.grid(row=1, column=1, padx=(10, 0), sticky=tk.N)
+        for row, (label, command) in enumerate((("Move Up", lambda: self.move_step(-1)),
+                                                 ("Move Down", lambda: self.move_step(1)),
+                                                 ("Toggle Approval", self.toggle_step),
+                                                 ("Delete", self.delete_step))):
+            ttk.Button(side, text=label, command=command).grid(row=row, column=0, pady=(0, 5), sticky=(tk.W, tk.E))
+
+        # Edit or add a step
+        ttk.Label(main_frame, text="Step text:").grid(row=2, column=0, sticky=tk.W, pady=(10, 5))
+        self.step_text = tk.Entry(main_frame, **self.text_style)
+        self.step_text.grid(row=3, column=0, sticky=(tk.W, tk.E), ipady=4)
+        edit_buttons = ttk.Frame(main_frame)
+        edit_buttons.grid(row=3, column=1, padx=(10, 0), sticky=tk.W)
+        ttk.Button(edit_buttons, text="Update", command=self.update_step).grid(row=0, column=0, padx=(0, 5))
+        ttk.Button(edit_buttons, text="Add", command=self.add_step).grid(row=0, column=1)
+
+        ttk.Label(main_frame, text="Comment:").grid(row=4, column=0, sticky=tk.W, pady=(10, 5))
+        comment_frame, self.comment = self.create_rounded_widget(
+            main_frame, tk.Text, height=3, wrap=tk.WORD, **self.text_style)
+        comment_frame.grid(row=5, column=0, columnspan=2, sticky=(tk.W, tk.E), pady=(0, 10))
+
+        actions = ttk.Frame(main_frame)
+        actions.grid(row=6, column=0, columnspan=2, sticky=tk.W)
+        ttk.Button(actions, text="Approve Plan", command=lambda: self.submit_plan('approved')).grid(row=0, column=0, padx=(0, 10))
+        ttk.Button(actions, text="Reject Plan", command=lambda: self.submit_plan('rejected')).grid(row=0, column=1, padx=(0, 10))
+        ttk.Button(actions, text="Cancel", command=self.cancel_feedback).grid(row=0, column=2)
+
+        self.refresh_list(0 if self.steps else None)
+        self.root.protocol("WM_DELETE_WINDOW", self.cancel_feedback)
+        self.root.mainloop()
+        return self.result
+
+    def step_label(self, number, step):
+        mark = '☑' if step['approved'] else '☐'
+        if step['original'] is None:
+            note = '  (added)'
+        elif step['text'] != self.proposed[step['original']]:
+            note = '  (edited)'
+        else:
+            note = ''
+        return f"{mark} {number}. {step['text']}{note}"
+
+    def refresh_list(self, selected=None):
+        self.step_list.delete(0, tk.END)
+        for number, step in enumerate(self.steps, start=1):
+            self.step_list.insert(tk.END, self.step_label(number, step))
+        if selected is not None and self.steps:
+            selected = max(0, min(selected, len(self.steps) - 1))
+            self.step_list.selection_set(selected)
+            self.step_list.see(selected)
+            self.on_select_step()
+
+    def selected_index(self):
+        selection = self.step_list.curselection()
+        return selection[0] if selection else None
+
+    def on_select_step(self, event=None):
+        index = self.selected_index()
+        if index is not None:
+            self.step_text.delete(0, tk.END)
+            self.step_text.insert(0, self.steps[index]['text'])
+
+    def move_step(self, offset):
+        index = self.selected_index()
+        if index is None or not 0 <= index + offset < len(self.steps):
+            return
+        self.steps[index], self.steps[index + offset] = self.steps[index + offset], self.steps[index]
+        self.refresh_list(index + offset)
+
+    def toggle_step(self):
+        index = self.selected_index()
+        if index is not None:
+            self.steps[index]['approved'] = not self.steps[index]['approved']
+            self.refresh_list(index)
+
+    def delete_step(self):
+        index = self.selected_index()
+        if index is not None:
+            del self.steps[index]
+            self.refresh_list(index)
+
+    def update_step(self):
+        index = self.selected_index()
+        text = self.step_text.get().strip()
+        if index is not None and text:
+            self.steps[index]['text'] = text
+            self.refresh_list(index)
+
+    def add_step(self):
+        text = self.step_text.get().strip()
+        if not text:
+            return
+        index = self.selected_index()
+        position = len(self.steps) if index is None else index + 1
+        self.steps.insert(position, {'text': text, 'original': None, 'approved': True})
+        self.refresh_list(position)
+
+    def submit_plan(self, verdict):
+        self.result = {
+            'steps': self.steps,
+            'verdict': verdict,
+            'comment': self.comment.get("1.0", tk.END).strip(),
+        }
+        self.root.quit()
+        self.root.destroy()
+
+    def cancel_feedback(self):
+        self.result = None
+        self.root.quit()
+        self.root.destroy()
+
+def edit_plan(request_file):
+    """Show the plan editor; prints the decisions as JSON, or nothing if cancelled"""
+    with open(request_file, 'r') as f:
+        request = json.load(f)
+    result = PlanEditorGUI(request).create_plan_dialog()
+    if result is not None:
+        print(json.dumps(result))
+
+def review_diff(request_file):
+    """Show the diff review window; prints the decisions as JSON, or nothing if cancelled"""
+    with open(request_file, 'r') as f:
+        request = json.load(f)
+    result = DiffReviewGUI(request).create_review_dialog()
+    if result is not None:
+        print(json.dumps(result))
+
+def confirm_command(project_directory, command):
+    """Ask whether a new or changed project command may run; prints approved or denied"""
+    try:
+        from tkinter import messagebox
+        root = tk.Tk()
+        root.withdraw()
+        root.attributes('-topmost', True)
+        approved = messagebox.askyesno(
+            "Run project command?",
+            f"The project configuration wants to run a new or changed command:\n\n"
+            f"{command}\n\nin {project_directory}\n\nRun it now and remember this approval?",
+            icon='warning', default='no', parent=root)
+        root.destroy()
+    except Exception:
+        approved = False  # Never run unapproved commands without a dialog
+    print("approved" if approved else "denied")
+
 def main():
-    if len(sys.argv) != 3:
-        print("Usage: python3 desktop_gui_single.py <project_directory> <prompt>")
+    if len(sys.argv) == 4 and sys.argv[1] == '--confirm':
+        confirm_command(sys.argv[2], sys.argv[3])
+        return
+    if len(sys.argv) == 3 and sys.argv[1] == '--review':
+        review_diff(sys.argv[2])
+        return
+    if len(sys.argv) == 3 and sys.argv[1] == '--plan':
+        edit_plan(sys.argv[2])
+        return
+
+    if len(sys.argv) not in (3, 4, 5, 6):
+        print("Usage: python3 desktop_gui_single.py <project_directory> <prompt> [config_file] [attachments_file] [prompt_attachments_file]")
+        print("       python3 desktop_gui_single.py --confirm <project_directory> <command>")
+        print("       python3 desktop_gui_single.py --review <request_file>")
+        print("       python3 desktop_gui_single.py --plan <request_file>")
         sys.exit(1)
     
     project_directory = sys.argv[1]
     prompt = sys.argv[2]
+    config_file = sys.argv[3] if len(sys.argv) >= 4 else None
+    attachments_file = sys.argv[4] if len(sys.argv) >= 5 else None
+    prompt_attachments_file = sys.argv[5] if len(sys.argv) == 6 else None
     
     # Create GUI without system notification
-    gui = SinglePopupDesktopGUI(project_directory, prompt)
+    gui = SinglePopupDesktopGUI(project_directory, prompt, config_file, attachments_file,
+                                prompt_attachments_file)
     
     # Create and show dialog
     feedback = gui.create_single_dialog()
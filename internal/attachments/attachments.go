@@ -0,0 +1,190 @@
+package attachments
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	_ "image/gif" // decoder for image.Decode
+	"image/jpeg"
+	"image/png"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/image/draw"
+	"interactive-feedback-mcp/internal/types"
+)
+
+// Limits bound what is returned to the agent
+type Limits struct {
+	MaxFileBytes  int64 // larger files, or images still larger once scaled down, are rejected
+	MaxImageBytes int64 // larger images are rejected before they are decoded
+	MaxTotalBytes int64 // attachments past this total are rejected
+	MaxImageSide  int   // larger images are scaled down to fit
+}
+
+// DefaultLimits keep a feedback result within what MCP clients accept
+var DefaultLimits = Limits{
+	MaxFileBytes:  10 * 1024 * 1024,
+	MaxImageBytes: 50 * 1024 * 1024,
+	MaxTotalBytes: 20 * 1024 * 1024,
+	MaxImageSide:  1568,
+}
+
+// imageTypes are the image formats returned as image content blocks
+var imageTypes = map[string]bool{
+	"image/png":  true,
+	"image/jpeg": true,
+	"image/gif":  true,
+	"image/webp": true,
+}
+
+// File is an attachment read from disk and ready to return
+type File struct {
+	types.Attachment
+	Data       []byte
+	Downscaled bool
+	Language   string // set for code snippets
+}
+
+// IsImage reports whether the file is returned as an image content block
+func (f *File) IsImage() bool {
+	return imageTypes[f.MimeType]
+}
+
+// IsText reports whether the file is returned as a text resource
+func (f *File) IsText() bool {
+	return strings.HasPrefix(f.MimeType, "text/") || f.MimeType == "application/json"
+}
+
+// LoadAll reads the files at paths, skipping the ones that break the limits.
+// The returned errors say which files were skipped and why.
+func LoadAll(paths []string, limits Limits) ([]*File, []error) {
+	var files []*File
+	var errs []error
+	var total int64
+	for _, path := range paths {
+		file, err := Load(path, limits)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if limits.MaxTotalBytes > 0 && total+int64(len(file.Data)) > limits.MaxTotalBytes {
+			errs = append(errs, fmt.Errorf("%s: skipped, attachments exceed %d bytes in total", file.Name, limits.MaxTotalBytes))
+			continue
+		}
+		total += int64(len(file.Data))
+		files = append(files, file)
+	}
+	return files, errs
+}
+
+// Load reads one file, detects its type and scales images down to the limits
+func Load(path string, limits Limits) (*File, error) {
+	name := filepath.Base(path)
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	if info.IsDir() {
+		return nil, fmt.Errorf("%s: is a directory", name)
+	}
+	if readLimit := max(limits.MaxFileBytes, limits.MaxImageBytes); readLimit > 0 && info.Size() > readLimit {
+		return nil, fmt.Errorf("%s: %d bytes exceeds the %d byte limit", name, info.Size(), readLimit)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+
+	file := &File{
+		Attachment: types.Attachment{
+			Name:     name,
+			MimeType: DetectMimeType(name, data),
+			Size:     int64(len(data)),
+			Path:     path,
+		},
+		Data: data,
+	}
+
+	if file.IsImage() && limits.MaxImageSide > 0 {
+		if err := downscale(file, limits.MaxImageSide); err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	if limits.MaxFileBytes > 0 && file.Size > limits.MaxFileBytes {
+		return nil, fmt.Errorf("%s: %d bytes exceeds the %d byte limit", name, file.Size, limits.MaxFileBytes)
+	}
+	return file, nil
+}
+
+// DetectMimeType sniffs the content, falling back to the file extension for
+// text formats that sniffing reports as plain text or octet streams
+func DetectMimeType(name string, data []byte) string {
+	detected := http.DetectContentType(data)
+	detected, _, _ = strings.Cut(detected, ";")
+
+	if detected == "text/plain" || detected == "application/octet-stream" {
+		if byExtension := mime.TypeByExtension(filepath.Ext(name)); byExtension != "" {
+			byExtension, _, _ = strings.Cut(byExtension, ";")
+			return byExtension
+		}
+		if detected == "application/octet-stream" && utf8.Valid(data) && !bytes.ContainsRune(data, 0) {
+			return "text/plain"
+		}
+	}
+	return detected
+}
+
+// maxImagePixels bounds the images downscale decodes, as decoding allocates
+// four bytes per pixel whatever the file size
+const maxImagePixels = 50_000_000
+
+// downscale re-encodes images whose longer side exceeds maxSide. Formats
+// that cannot be decoded (e.g. SVG or WebP) are returned unchanged.
+func downscale(file *File, maxSide int) error {
+	config, format, err := image.DecodeConfig(bytes.NewReader(file.Data))
+	if err != nil || (config.Width <= maxSide && config.Height <= maxSide) {
+		return nil
+	}
+	if pixels := int64(config.Width) * int64(config.Height); pixels > maxImagePixels {
+		return fmt.Errorf("image of %dx%d pixels exceeds the %d pixel limit", config.Width, config.Height, maxImagePixels)
+	}
+
+	source, _, err := image.Decode(bytes.NewReader(file.Data))
+	if err != nil {
+		return fmt.Errorf("failed to decode image: %w", err)
+	}
+
+	width, height := config.Width, config.Height
+	if width >= height {
+		height = max(1, height*maxSide/width)
+		width = maxSide
+	} else {
+		width = max(1, width*maxSide/height)
+		height = maxSide
+	}
+	scaled := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.CatmullRom.Scale(scaled, scaled.Bounds(), source, source.Bounds(), draw.Over, nil)
+
+	var encoded bytes.Buffer
+	switch format {
+	case "jpeg":
+		err = jpeg.Encode(&encoded, scaled, &jpeg.Options{Quality: 85})
+	default: // PNG, and GIF whose animation would be lost anyway
+		err = png.Encode(&encoded, scaled)
+		file.MimeType = "image/png"
+	}
+	if err != nil {
+		return fmt.Errorf("failed to encode image: %w", err)
+	}
+
+	file.Data = encoded.Bytes()
+	file.Size = int64(len(file.Data))
+	file.Downscaled = true
+	return nil
+}
@@ -0,0 +1,128 @@
+package attachments
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeFile(t *testing.T, dir, name string, data []byte) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.WriteFile(path, data, 0644))
+	return path
+}
+
+func encodePNG(t *testing.T, width, height int) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for x := 0; x < width; x++ {
+		img.Set(x, x*height/width, color.RGBA{R: 255, A: 255})
+	}
+	var buffer bytes.Buffer
+	require.NoError(t, png.Encode(&buffer, img))
+	return buffer.Bytes()
+}
+
+func TestDetectMimeType(t *testing.T) {
+	tests := map[string]struct {
+		name     string
+		data     []byte
+		expected string
+	}{
+		"png by content":        {"shot", []byte("\x89PNG\r\n\x1a\n0000"), "image/png"},
+		"markdown by extension": {"notes.md", []byte("# Notes"), "text/markdown"},
+		"json by extension":     {"data.json", []byte(`{"a": 1}`), "application/json"},
+		"utf-8 without ext":     {"README", []byte("héllo"), "text/plain"},
+		"binary":                {"blob", []byte{0, 1, 2, 3}, "application/octet-stream"},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.expected, DetectMimeType(test.name, test.data))
+		})
+	}
+}
+
+func TestLoad_Text(t *testing.T) {
+	path := writeFile(t, t.TempDir(), "app.log", []byte("started\n"))
+
+	file, err := Load(path, DefaultLimits)
+	require.NoError(t, err)
+	assert.Equal(t, "app.log", file.Name)
+	assert.Equal(t, int64(8), file.Size)
+	assert.Equal(t, path, file.Path)
+	assert.True(t, file.IsText())
+	assert.False(t, file.IsImage())
+}
+
+func TestLoad_DownscalesLargeImages(t *testing.T) {
+	path := writeFile(t, t.TempDir(), "screen.png", encodePNG(t, 400, 100))
+
+	file, err := Load(path, Limits{MaxImageSide: 200})
+	require.NoError(t, err)
+	assert.True(t, file.IsImage())
+	assert.True(t, file.Downscaled)
+	assert.Equal(t, "image/png", file.MimeType)
+	assert.Equal(t, int64(len(file.Data)), file.Size)
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(file.Data))
+	require.NoError(t, err)
+	assert.Equal(t, 200, config.Width)
+	assert.Equal(t, 50, config.Height)
+
+	// Images that already fit are returned unchanged
+	file, err = Load(path, Limits{MaxImageSide: 400})
+	require.NoError(t, err)
+	assert.False(t, file.Downscaled)
+}
+
+func TestLoad_RejectsHugeImages(t *testing.T) {
+	// A few hundred bytes of PNG can declare 50000x50000 pixels; only the
+	// header is read, so the pixels are never allocated
+	header := encodePNG(t, 1, 1)[:33]
+	binary.BigEndian.PutUint32(header[16:], 50000)
+	binary.BigEndian.PutUint32(header[20:], 50000)
+	binary.BigEndian.PutUint32(header[29:], crc32.ChecksumIEEE(header[12:29]))
+	path := writeFile(t, t.TempDir(), "bomb.png", header)
+
+	_, err := Load(path, DefaultLimits)
+	assert.ErrorContains(t, err, "bomb.png: image of 50000x50000 pixels exceeds the 50000000 pixel limit")
+}
+
+func TestLoad_Limits(t *testing.T) {
+	dir := t.TempDir()
+
+	_, err := Load(writeFile(t, dir, "big.txt", bytes.Repeat([]byte("a"), 100)), Limits{MaxFileBytes: 10})
+	assert.ErrorContains(t, err, "big.txt: 100 bytes exceeds the 10 byte limit")
+
+	_, err = Load(filepath.Join(dir, "missing.txt"), DefaultLimits)
+	assert.ErrorContains(t, err, "missing.txt")
+
+	_, err = Load(dir, DefaultLimits)
+	assert.ErrorContains(t, err, "is a directory")
+}
+
+func TestLoadAll_TotalLimit(t *testing.T) {
+	dir := t.TempDir()
+	first := writeFile(t, dir, "a.txt", bytes.Repeat([]byte("a"), 6))
+	second := writeFile(t, dir, "b.txt", bytes.Repeat([]byte("b"), 6))
+	third := writeFile(t, dir, "c.txt", []byte("c"))
+
+	files, errs := LoadAll([]string{first, second, third, filepath.Join(dir, "gone")}, Limits{MaxTotalBytes: 10})
+	require.Len(t, files, 2)
+	assert.Equal(t, "a.txt", files[0].Name)
+	assert.Equal(t, "c.txt", files[1].Name)
+	require.Len(t, errs, 2)
+	assert.ErrorContains(t, errs[0], "b.txt: skipped")
+	assert.ErrorContains(t, errs[1], "gone")
+}
@@ -0,0 +1,121 @@
+package attachments
+
+import (
+	"encoding/base64"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"interactive-feedback-mcp/internal/types"
+	"interactive-feedback-mcp/internal/workspace"
+)
+
+// Kinds of attachment an agent can send with its prompt
+const (
+	PromptImage   = "image"
+	PromptFile    = "file"
+	PromptSnippet = "snippet"
+)
+
+// PromptItem is an attachment an agent sends with its prompt
+type PromptItem struct {
+	Type     string `json:"type"`
+	Name     string `json:"name,omitempty"`
+	MimeType string `json:"mimeType,omitempty"`
+	Data     string `json:"data,omitempty"`     // base64 image data
+	Path     string `json:"path,omitempty"`     // file inside the project
+	Text     string `json:"text,omitempty"`     // snippet text
+	Language string `json:"language,omitempty"` // snippet language, e.g. go
+}
+
+// ResolvePrompt checks the agent's attachments and loads them. Unlike the
+// user's attachments, any invalid item fails the whole call.
+func ResolvePrompt(projectDir string, items []PromptItem, limits Limits) ([]*File, error) {
+	var files []*File
+	var total int64
+	for i, item := range items {
+		file, err := resolvePromptItem(projectDir, item, limits)
+		if err != nil {
+			return nil, fmt.Errorf("attachments[%d]: %w", i, err)
+		}
+		total += file.Size
+		if limits.MaxTotalBytes > 0 && total > limits.MaxTotalBytes {
+			return nil, fmt.Errorf("attachments exceed %d bytes in total", limits.MaxTotalBytes)
+		}
+		files = append(files, file)
+	}
+	return files, nil
+}
+
+func resolvePromptItem(projectDir string, item PromptItem, limits Limits) (*File, error) {
+	switch item.Type {
+	case PromptImage:
+		data, err := base64.StdEncoding.DecodeString(item.Data)
+		if err != nil {
+			return nil, fmt.Errorf("data is not valid base64: %w", err)
+		}
+		if limits.MaxFileBytes > 0 && int64(len(data)) > limits.MaxFileBytes {
+			return nil, fmt.Errorf("image of %d bytes exceeds the %d byte limit", len(data), limits.MaxFileBytes)
+		}
+		name := item.Name
+		if name == "" {
+			name = "image"
+		}
+		file := &File{
+			Attachment: types.Attachment{Name: name, MimeType: DetectMimeType(name, data), Size: int64(len(data))},
+			Data:       data,
+		}
+		if !file.IsImage() {
+			return nil, fmt.Errorf("data is %s, not a PNG, JPEG, GIF or WebP image", file.MimeType)
+		}
+		if item.MimeType != "" && item.MimeType != file.MimeType {
+			return nil, fmt.Errorf("mimeType %s does not match the data, which is %s", item.MimeType, file.MimeType)
+		}
+		return file, nil
+
+	case PromptFile:
+		if item.Path == "" {
+			return nil, fmt.Errorf("path is required for a file")
+		}
+		path := item.Path
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(projectDir, path)
+		}
+		canonical, err := filepath.EvalSymlinks(path)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read %s: %w", item.Path, err)
+		}
+		if !workspace.Contains(projectDir, canonical) {
+			return nil, fmt.Errorf("%s is outside the project", item.Path)
+		}
+		file, err := Load(canonical, Limits{MaxFileBytes: limits.MaxFileBytes})
+		if err != nil {
+			return nil, err
+		}
+		if item.Name != "" {
+			file.Name = item.Name
+		}
+		return file, nil
+
+	case PromptSnippet:
+		if item.Text == "" {
+			return nil, fmt.Errorf("text is required for a snippet")
+		}
+		if limits.MaxFileBytes > 0 && int64(len(item.Text)) > limits.MaxFileBytes {
+			return nil, fmt.Errorf("snippet of %d bytes exceeds the %d byte limit", len(item.Text), limits.MaxFileBytes)
+		}
+		name := item.Name
+		if name == "" {
+			name = "snippet"
+			if item.Language != "" {
+				name += " (" + item.Language + ")"
+			}
+		}
+		return &File{
+			Attachment: types.Attachment{Name: name, MimeType: "text/plain", Size: int64(len(item.Text))},
+			Data:       []byte(item.Text),
+			Language:   strings.ToLower(item.Language),
+		}, nil
+	}
+	return nil, fmt.Errorf("unknown type %q", item.Type)
+}
@@ -0,0 +1,80 @@
+package attachments
+
+import (
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolvePrompt(t *testing.T) {
+	projectDir, err := filepath.EvalSymlinks(t.TempDir())
+	require.NoError(t, err)
+	writeFile(t, projectDir, "main.go", []byte("package main\n"))
+	encoded := base64.StdEncoding.EncodeToString(encodePNG(t, 4, 4))
+
+	files, err := ResolvePrompt(projectDir, []PromptItem{
+		{Type: PromptImage, Name: "chart.png", Data: encoded},
+		{Type: PromptFile, Path: "main.go"},
+		{Type: PromptSnippet, Text: "SELECT 1", Language: "SQL"},
+	}, DefaultLimits)
+	require.NoError(t, err)
+	require.Len(t, files, 3)
+
+	assert.Equal(t, "chart.png", files[0].Name)
+	assert.Equal(t, "image/png", files[0].MimeType)
+	assert.True(t, files[0].IsImage())
+
+	assert.Equal(t, "main.go", files[1].Name)
+	assert.Equal(t, filepath.Join(projectDir, "main.go"), files[1].Path)
+	assert.Equal(t, "package main\n", string(files[1].Data))
+
+	assert.Equal(t, "snippet (SQL)", files[2].Name)
+	assert.Equal(t, "sql", files[2].Language)
+	assert.Equal(t, "SELECT 1", string(files[2].Data))
+}
+
+func TestResolvePrompt_Invalid(t *testing.T) {
+	projectDir, err := filepath.EvalSymlinks(t.TempDir())
+	require.NoError(t, err)
+	outside := writeFile(t, t.TempDir(), "secret.txt", []byte("secret"))
+	require.NoError(t, os.Symlink(outside, filepath.Join(projectDir, "link.txt")))
+	png := base64.StdEncoding.EncodeToString(encodePNG(t, 4, 4))
+
+	tests := map[string]struct {
+		item     PromptItem
+		expected string
+	}{
+		"bad base64":       {PromptItem{Type: PromptImage, Data: "%%%"}, "not valid base64"},
+		"not an image":     {PromptItem{Type: PromptImage, Data: base64.StdEncoding.EncodeToString([]byte("hello"))}, "not a PNG"},
+		"mismatched type":  {PromptItem{Type: PromptImage, Data: png, MimeType: "image/jpeg"}, "does not match"},
+		"missing path":     {PromptItem{Type: PromptFile}, "path is required"},
+		"missing file":     {PromptItem{Type: PromptFile, Path: "nope.txt"}, "cannot read nope.txt"},
+		"absolute outside": {PromptItem{Type: PromptFile, Path: outside}, "outside the project"},
+		"symlink outside":  {PromptItem{Type: PromptFile, Path: "link.txt"}, "outside the project"},
+		"empty snippet":    {PromptItem{Type: PromptSnippet}, "text is required"},
+		"unknown type":     {PromptItem{Type: "video"}, `attachments[0]: unknown type "video"`},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := ResolvePrompt(projectDir, []PromptItem{test.item}, DefaultLimits)
+			assert.ErrorContains(t, err, test.expected)
+		})
+	}
+}
+
+func TestResolvePrompt_Limits(t *testing.T) {
+	_, err := ResolvePrompt(t.TempDir(), []PromptItem{{Type: PromptSnippet, Text: "0123456789abcdef"}}, Limits{MaxFileBytes: 10})
+	assert.ErrorContains(t, err, "snippet of 16 bytes exceeds the 10 byte limit")
+
+	items := []PromptItem{
+		{Type: PromptSnippet, Text: "123456"},
+		{Type: PromptSnippet, Text: "123456"},
+	}
+	_, err = ResolvePrompt(t.TempDir(), items, Limits{MaxTotalBytes: 10})
+	assert.ErrorContains(t, err, "exceed 10 bytes in total")
+}
@@ -0,0 +1,63 @@
+package config
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+// CommandHash identifies a project command for approval. Callers pass the
+// command's executor.LaunchSpec so that its shell and environment are covered
+// too. The hash covers the project path so that an approval does not carry
+// over to other checkouts.
+func CommandHash(projectPath, spec string) string {
+	sum := sha256.Sum256([]byte(canonicalProjectPath(projectPath) + "\x00" + strings.TrimSpace(spec)))
+	return hex.EncodeToString(sum[:])
+}
+
+// IsCommandApproved reports whether command may run in projectPath without
+// asking. Every command is approved unless require_command_approval is set.
+func (cm *ConfigManager) IsCommandApproved(projectPath, command string) (bool, error) {
+	userConfig, err := cm.LoadUserConfig()
+	if err != nil {
+		return false, err
+	}
+	if !userConfig.RequireCommandApproval {
+		return true, nil
+	}
+	return cm.HasCommandApproval(projectPath, command)
+}
+
+// HasCommandApproval reports whether the user has explicitly approved command
+// for projectPath, whatever the require_command_approval setting
+func (cm *ConfigManager) HasCommandApproval(projectPath, command string) (bool, error) {
+	userConfig, err := cm.LoadUserConfig()
+	if err != nil {
+		return false, err
+	}
+
+	hash := CommandHash(projectPath, command)
+	for _, approved := range userConfig.ApprovedCommands {
+		if approved == hash {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// ApproveCommand records the user's approval of command for projectPath
+func (cm *ConfigManager) ApproveCommand(projectPath, command string) error {
+	userConfig, err := cm.LoadUserConfig()
+	if err != nil {
+		return err
+	}
+
+	hash := CommandHash(projectPath, command)
+	for _, approved := range userConfig.ApprovedCommands {
+		if approved == hash {
+			return nil
+		}
+	}
+	userConfig.ApprovedCommands = append(userConfig.ApprovedCommands, hash)
+	return cm.SaveUserConfig(userConfig)
+}
@@ -0,0 +1,66 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// FieldNames lists the JSON names of the settings in a config struct
+func FieldNames(settings interface{}) []string {
+	var names []string
+	structType := reflect.TypeOf(settings).Elem()
+	for i := 0; i < structType.NumField(); i++ {
+		if name := jsonName(structType.Field(i)); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// GetField returns a setting, named as in the config file, as JSON
+func GetField(settings interface{}, key string) (json.RawMessage, error) {
+	field, err := lookupField(settings, key)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(field.Interface())
+}
+
+// SetField changes a setting, named as in the config file. The value is
+// read as JSON; string settings also take it verbatim.
+func SetField(settings interface{}, key, value string) error {
+	field, err := lookupField(settings, key)
+	if err != nil {
+		return err
+	}
+
+	parsed := reflect.New(field.Type())
+	if err := json.Unmarshal([]byte(value), parsed.Interface()); err != nil {
+		if field.Kind() != reflect.String {
+			return fmt.Errorf("invalid value for %s: %w", key, err)
+		}
+		parsed.Elem().SetString(value)
+	}
+	field.Set(parsed.Elem())
+	return nil
+}
+
+func lookupField(settings interface{}, key string) (reflect.Value, error) {
+	value := reflect.ValueOf(settings).Elem()
+	for i := 0; i < value.NumField(); i++ {
+		if jsonName(value.Type().Field(i)) == key {
+			return value.Field(i), nil
+		}
+	}
+	return reflect.Value{}, fmt.Errorf("unknown setting %q (expected one of: %s)", key, strings.Join(FieldNames(settings), ", "))
+}
+
+func jsonName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	if name == "-" || !field.IsExported() {
+		return ""
+	}
+	return name
+}
@@ -0,0 +1,52 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"interactive-feedback-mcp/internal/types"
+)
+
+func TestGetField(t *testing.T) {
+	projectConfig := &types.ProjectConfig{RunCommand: "make test", Env: map[string]string{"CI": "1"}}
+
+	value, err := GetField(projectConfig, "run_command")
+	require.NoError(t, err)
+	assert.JSONEq(t, `"make test"`, string(value))
+
+	value, err = GetField(projectConfig, "env")
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"CI": "1"}`, string(value))
+
+	_, err = GetField(projectConfig, "RunCommand")
+	assert.ErrorContains(t, err, `unknown setting "RunCommand"`)
+}
+
+func TestSetField(t *testing.T) {
+	projectConfig := &types.ProjectConfig{}
+
+	// Strings are taken verbatim unless they are JSON strings
+	require.NoError(t, SetField(projectConfig, "run_command", "go test ./..."))
+	assert.Equal(t, "go test ./...", projectConfig.RunCommand)
+	require.NoError(t, SetField(projectConfig, "shell", `"zsh"`))
+	assert.Equal(t, "zsh", projectConfig.Shell)
+
+	require.NoError(t, SetField(projectConfig, "execute_automatically", "true"))
+	assert.True(t, projectConfig.ExecuteAutomatically)
+	require.NoError(t, SetField(projectConfig, "disabled_tools", `["approve_plan"]`))
+	assert.Equal(t, []string{"approve_plan"}, projectConfig.DisabledTools)
+	require.NoError(t, SetField(projectConfig, "stop_interrupt_seconds", "1.5"))
+	assert.Equal(t, 1.5, *projectConfig.StopInterruptSeconds)
+	require.NoError(t, SetField(projectConfig, "stop_interrupt_seconds", "null"))
+	assert.Nil(t, projectConfig.StopInterruptSeconds)
+
+	assert.ErrorContains(t, SetField(projectConfig, "execute_automatically", "yes"), "invalid value for execute_automatically")
+	assert.ErrorContains(t, SetField(projectConfig, "nope", "1"), "unknown setting")
+}
+
+func TestFieldNames(t *testing.T) {
+	names := FieldNames(&types.UserConfig{})
+	assert.Contains(t, names, "gitignore_policy")
+	assert.Contains(t, names, "config_storage")
+}
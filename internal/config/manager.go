@@ -3,31 +3,156 @@ package config
 import (
 	"encoding/json"
 	"fmt"
+	"log"
 	"os"
 	"path/filepath"
 
 	"interactive-feedback-mcp/internal/types"
 )
 
+// ProjectConfigFileName is the per-project config file written into the project directory
+const ProjectConfigFileName = ".interactive-feedback-config.json"
+
 type ConfigManager struct {
+	userConfigDir  string
+	projectDataDir string
+	storage        string
 }
 
 func NewConfigManager() (*ConfigManager, error) {
-	return &ConfigManager{}, nil
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to locate user config directory: %w", err)
+	}
+
+	cm := &ConfigManager{
+		userConfigDir: filepath.Join(dir, "interactive-feedback-mcp"),
+		storage:       StorageProject,
+	}
+
+	// A broken user config is reported by LoadUserConfig wherever its settings
+	// are needed; here it only falls back to keeping configs in the project
+	userConfig, err := cm.LoadUserConfig()
+	if err != nil {
+		log.Printf("%v; keeping project configs in their projects", err)
+		return cm, nil
+	}
+
+	switch userConfig.ConfigStorage {
+	case "", StorageProject:
+	case StorageUser:
+		dataDir, err := userDataDir()
+		if err != nil {
+			log.Printf("Failed to locate user data directory: %v; keeping project configs in their projects", err)
+			return cm, nil
+		}
+		cm.storage = StorageUser
+		cm.projectDataDir = filepath.Join(dataDir, "interactive-feedback-mcp", "projects")
+	default:
+		log.Printf("Unknown config_storage %q (expected project or user); keeping project configs in their projects", userConfig.ConfigStorage)
+	}
+
+	return cm, nil
+}
+
+// Storage returns where project configs are kept: StorageProject or StorageUser
+func (cm *ConfigManager) Storage() string {
+	return cm.storage
+}
+
+// UserConfigDir returns the directory holding user-level settings shared by all projects
+func (cm *ConfigManager) UserConfigDir() string {
+	return cm.userConfigDir
+}
+
+// UserConfigPath returns the user-level config file
+func (cm *ConfigManager) UserConfigPath() string {
+	return filepath.Join(cm.userConfigDir, "config.json")
+}
+
+// LoadUserConfig reads the user-level config, returning defaults if it is missing
+func (cm *ConfigManager) LoadUserConfig() (*types.UserConfig, error) {
+	config := &types.UserConfig{}
+
+	data, err := os.ReadFile(cm.UserConfigPath())
+	if os.IsNotExist(err) {
+		return config, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read user config: %w", err)
+	}
+
+	if err := json.Unmarshal(data, config); err != nil {
+		return nil, fmt.Errorf("failed to parse user config %s: %w", cm.UserConfigPath(), err)
+	}
+
+	return config, nil
+}
+
+func (cm *ConfigManager) SaveUserConfig(config *types.UserConfig) error {
+	data, err := json.MarshalIndent(config, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal user config: %w", err)
+	}
+
+	if err := os.MkdirAll(cm.userConfigDir, 0755); err != nil {
+		return fmt.Errorf("failed to create user config directory: %w", err)
+	}
+
+	if err := os.WriteFile(cm.UserConfigPath(), data, 0644); err != nil {
+		return fmt.Errorf("failed to write user config: %w", err)
+	}
+
+	return nil
 }
 
+// ProjectConfigPath returns the config file used for a project. With user
+// storage it lives under the user data directory, keyed by a hash of the
+// canonical project path.
+func (cm *ConfigManager) ProjectConfigPath(projectPath string) string {
+	if cm.storage == StorageUser {
+		return filepath.Join(cm.projectDataDir, projectKey(projectPath), "config.json")
+	}
+	return filepath.Join(projectPath, ProjectConfigFileName)
+}
 
+// LoadProjectConfig reads a project's config, returning defaults if it is
+// missing or cannot be read
 func (cm *ConfigManager) LoadProjectConfig(projectPath string) *types.ProjectConfig {
-	// Load from project directory
-	configFile := filepath.Join(projectPath, ".interactive-feedback-config.json")
-	if data, err := os.ReadFile(configFile); err == nil {
-		var config types.ProjectConfig
-		if json.Unmarshal(data, &config) == nil {
-			return &config
+	config, err := cm.ReadProjectConfig(projectPath)
+	if err != nil {
+		log.Printf("%v; using defaults", err)
+		return defaultProjectConfig()
+	}
+	return config
+}
+
+// ReadProjectConfig is LoadProjectConfig for callers that must not save
+// defaults over a file they could not read. A missing file gives defaults.
+func (cm *ConfigManager) ReadProjectConfig(projectPath string) (*types.ProjectConfig, error) {
+	if cm.storage == StorageUser {
+		if err := cm.migrateProjectConfig(projectPath); err != nil {
+			log.Printf("Failed to move %s to user storage: %v", ProjectConfigFileName, err)
 		}
 	}
 
-	// Return default config if not found
+	configFile := cm.ProjectConfigPath(projectPath)
+	data, err := os.ReadFile(configFile)
+	if os.IsNotExist(err) {
+		return defaultProjectConfig(), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read project config: %w", err)
+	}
+
+	var config types.ProjectConfig
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse project config %s: %w", configFile, err)
+	}
+	return &config, nil
+}
+
+func defaultProjectConfig() *types.ProjectConfig {
 	return &types.ProjectConfig{
 		RunCommand:            "",
 		ExecuteAutomatically:  false,
@@ -37,13 +162,25 @@ func (cm *ConfigManager) LoadProjectConfig(projectPath string) *types.ProjectCon
 }
 
 func (cm *ConfigManager) SaveProjectConfig(projectPath string, config *types.ProjectConfig) error {
-	configFile := filepath.Join(projectPath, ".interactive-feedback-config.json")
-	
+	if cm.storage == StorageUser {
+		if err := cm.migrateProjectConfig(projectPath); err != nil {
+			return err
+		}
+	}
+
+	configFile := cm.ProjectConfigPath(projectPath)
+
 	data, err := json.MarshalIndent(config, "", "  ")
 	if err != nil {
 		return fmt.Errorf("failed to marshal config: %w", err)
 	}
 
+	if cm.storage == StorageUser {
+		if err := os.MkdirAll(filepath.Dir(configFile), 0755); err != nil {
+			return fmt.Errorf("failed to create config directory: %w", err)
+		}
+	}
+
 	if err := os.WriteFile(configFile, data, 0644); err != nil {
 		return fmt.Errorf("failed to write config file: %w", err)
 	}
@@ -10,7 +10,17 @@ import (
 	"interactive-feedback-mcp/internal/types"
 )
 
+// isolateUserDirs points the user config and data directories at temporary
+// ones, so tests never read or write the real ones
+func isolateUserDirs(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+}
+
 func TestNewConfigManager(t *testing.T) {
+	isolateUserDirs(t)
+
 	// Test successful creation
 	manager, err := NewConfigManager()
 	require.NoError(t, err)
@@ -18,6 +28,8 @@ func TestNewConfigManager(t *testing.T) {
 }
 
 func TestConfigManager_LoadProjectConfig(t *testing.T) {
+	isolateUserDirs(t)
+
 	manager, err := NewConfigManager()
 	require.NoError(t, err)
 
@@ -31,6 +43,8 @@ func TestConfigManager_LoadProjectConfig(t *testing.T) {
 }
 
 func TestConfigManager_SaveProjectConfig(t *testing.T) {
+	isolateUserDirs(t)
+
 	manager, err := NewConfigManager()
 	require.NoError(t, err)
 
@@ -71,6 +85,8 @@ func TestConfigManager_SaveProjectConfig(t *testing.T) {
 
 
 func TestConfigManager_InvalidJSON(t *testing.T) {
+	isolateUserDirs(t)
+
 	manager, err := NewConfigManager()
 	require.NoError(t, err)
 
@@ -91,4 +107,36 @@ func TestConfigManager_InvalidJSON(t *testing.T) {
 	config := manager.LoadProjectConfig(projectPath)
 	assert.NotNil(t, config)
 	assert.Empty(t, config.RunCommand)
+
+	// ReadProjectConfig reports it instead
+	_, err = manager.ReadProjectConfig(projectPath)
+	assert.ErrorContains(t, err, "failed to parse project config")
+
+	config, err = manager.ReadProjectConfig(t.TempDir())
+	require.NoError(t, err)
+	assert.Empty(t, config.ConversationHistory)
+}
+
+func TestConfigManager_UserConfig(t *testing.T) {
+	isolateUserDirs(t)
+
+	manager, err := NewConfigManager()
+	require.NoError(t, err)
+
+	// Missing file returns defaults
+	userConfig, err := manager.LoadUserConfig()
+	require.NoError(t, err)
+	assert.Empty(t, userConfig.AllowedRoots)
+
+	userConfig.AllowedRoots = []string{"/work"}
+	require.NoError(t, manager.SaveUserConfig(userConfig))
+
+	loaded, err := manager.LoadUserConfig()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"/work"}, loaded.AllowedRoots)
+
+	// Invalid JSON is reported rather than silently ignored
+	require.NoError(t, os.WriteFile(manager.UserConfigPath(), []byte("invalid json"), 0644))
+	_, err = manager.LoadUserConfig()
+	assert.Error(t, err)
 }
@@ -0,0 +1,60 @@
+package config
+
+import (
+	"strings"
+
+	"interactive-feedback-mcp/internal/types"
+)
+
+// LegacyProfileName names the profile built from run_command
+const LegacyProfileName = "default"
+
+// CommandProfiles returns the project's named commands. Projects that only
+// set run_command get a single default profile built from it.
+func CommandProfiles(projectConfig *types.ProjectConfig) []types.CommandProfile {
+	if len(projectConfig.Commands) > 0 {
+		return projectConfig.Commands
+	}
+	if strings.TrimSpace(projectConfig.RunCommand) == "" {
+		return nil
+	}
+	return []types.CommandProfile{{
+		Name:    LegacyProfileName,
+		Command: projectConfig.RunCommand,
+		AutoRun: projectConfig.ExecuteAutomatically,
+		Default: true,
+	}}
+}
+
+// DefaultCommandProfile returns the profile marked default, or the first one
+func DefaultCommandProfile(projectConfig *types.ProjectConfig) (types.CommandProfile, bool) {
+	profiles := CommandProfiles(projectConfig)
+	for _, profile := range profiles {
+		if profile.Default {
+			return profile, true
+		}
+	}
+	if len(profiles) > 0 {
+		return profiles[0], true
+	}
+	return types.CommandProfile{}, false
+}
+
+// FindCommandProfile looks up a profile by name
+func FindCommandProfile(projectConfig *types.ProjectConfig, name string) (types.CommandProfile, bool) {
+	for _, profile := range CommandProfiles(projectConfig) {
+		if profile.Name == name {
+			return profile, true
+		}
+	}
+	return types.CommandProfile{}, false
+}
+
+// CommandProfileNames lists the profile names in configuration order
+func CommandProfileNames(projectConfig *types.ProjectConfig) []string {
+	var names []string
+	for _, profile := range CommandProfiles(projectConfig) {
+		names = append(names, profile.Name)
+	}
+	return names
+}
@@ -0,0 +1,50 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"interactive-feedback-mcp/internal/types"
+)
+
+func TestCommandProfiles_Legacy(t *testing.T) {
+	assert.Empty(t, CommandProfiles(&types.ProjectConfig{}))
+
+	projectConfig := &types.ProjectConfig{RunCommand: "make test", ExecuteAutomatically: true}
+	assert.Equal(t, []types.CommandProfile{
+		{Name: LegacyProfileName, Command: "make test", AutoRun: true, Default: true},
+	}, CommandProfiles(projectConfig))
+}
+
+func TestCommandProfiles_Named(t *testing.T) {
+	projectConfig := &types.ProjectConfig{
+		RunCommand: "ignored",
+		Commands: []types.CommandProfile{
+			{Name: "lint", Command: "make lint"},
+			{Name: "test", Command: "make test", Default: true},
+		},
+	}
+
+	assert.Equal(t, []string{"lint", "test"}, CommandProfileNames(projectConfig))
+
+	profile, ok := DefaultCommandProfile(projectConfig)
+	assert.True(t, ok)
+	assert.Equal(t, "test", profile.Name)
+
+	profile, ok = FindCommandProfile(projectConfig, "lint")
+	assert.True(t, ok)
+	assert.Equal(t, "make lint", profile.Command)
+
+	_, ok = FindCommandProfile(projectConfig, "e2e")
+	assert.False(t, ok)
+}
+
+func TestDefaultCommandProfile_FirstWhenUnmarked(t *testing.T) {
+	projectConfig := &types.ProjectConfig{Commands: []types.CommandProfile{{Name: "a"}, {Name: "b"}}}
+	profile, ok := DefaultCommandProfile(projectConfig)
+	assert.True(t, ok)
+	assert.Equal(t, "a", profile.Name)
+
+	_, ok = DefaultCommandProfile(&types.ProjectConfig{})
+	assert.False(t, ok)
+}
@@ -0,0 +1,90 @@
+package config
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+)
+
+const (
+	// StorageProject keeps the config file inside the project directory
+	StorageProject = "project"
+	// StorageUser keeps per-project state under the user data directory
+	StorageUser = "user"
+)
+
+// userDataDir returns the per-user application data directory, following
+// XDG on Linux and the platform conventions elsewhere.
+func userDataDir() (string, error) {
+	if dir := os.Getenv("XDG_DATA_HOME"); dir != "" {
+		return dir, nil
+	}
+
+	switch runtime.GOOS {
+	case "windows":
+		if dir := os.Getenv("LocalAppData"); dir != "" {
+			return dir, nil
+		}
+		return "", fmt.Errorf("%%LocalAppData%% is not defined")
+	case "darwin":
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		return filepath.Join(home, "Library", "Application Support"), nil
+	default:
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		return filepath.Join(home, ".local", "share"), nil
+	}
+}
+
+// canonicalProjectPath resolves symlinks so that every spelling of a project
+// maps to the same storage key
+func canonicalProjectPath(projectPath string) string {
+	abs, err := filepath.Abs(projectPath)
+	if err != nil {
+		return filepath.Clean(projectPath)
+	}
+	if resolved, err := filepath.EvalSymlinks(abs); err == nil {
+		return resolved
+	}
+	return abs
+}
+
+// projectKey is the directory name used for a project under the user data directory
+func projectKey(projectPath string) string {
+	sum := sha256.Sum256([]byte(canonicalProjectPath(projectPath)))
+	return hex.EncodeToString(sum[:16])
+}
+
+// migrateProjectConfig moves an in-repo config file to user storage the first
+// time a project is used with user storage enabled
+func (cm *ConfigManager) migrateProjectConfig(projectPath string) error {
+	legacyFile := filepath.Join(projectPath, ProjectConfigFileName)
+	data, err := os.ReadFile(legacyFile)
+	if err != nil {
+		return nil
+	}
+
+	configFile := cm.ProjectConfigPath(projectPath)
+	if _, err := os.Stat(configFile); err == nil {
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(configFile), 0755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+	if err := os.WriteFile(configFile, data, 0644); err != nil {
+		return fmt.Errorf("failed to migrate config file: %w", err)
+	}
+
+	// Leave the old file in place if it cannot be removed (e.g. read-only checkout)
+	os.Remove(legacyFile)
+	return nil
+}
@@ -0,0 +1,100 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"interactive-feedback-mcp/internal/types"
+)
+
+func newUserStorageManager(t *testing.T) *ConfigManager {
+	isolateUserDirs(t)
+
+	manager, err := NewConfigManager()
+	require.NoError(t, err)
+	require.NoError(t, manager.SaveUserConfig(&types.UserConfig{ConfigStorage: StorageUser}))
+
+	manager, err = NewConfigManager()
+	require.NoError(t, err)
+	require.Equal(t, StorageUser, manager.Storage())
+	return manager
+}
+
+func TestConfigManager_UserStorage(t *testing.T) {
+	manager := newUserStorageManager(t)
+	projectPath := t.TempDir()
+
+	configFile := manager.ProjectConfigPath(projectPath)
+	assert.Equal(t, filepath.Join(os.Getenv("XDG_DATA_HOME"), "interactive-feedback-mcp", "projects"), filepath.Dir(filepath.Dir(configFile)))
+
+	err := manager.SaveProjectConfig(projectPath, &types.ProjectConfig{RunCommand: "make test"})
+	require.NoError(t, err)
+
+	// Nothing is written into the project
+	_, err = os.Stat(filepath.Join(projectPath, ProjectConfigFileName))
+	assert.True(t, os.IsNotExist(err))
+
+	loaded := manager.LoadProjectConfig(projectPath)
+	assert.Equal(t, "make test", loaded.RunCommand)
+}
+
+func TestConfigManager_UserStorageKeyIsCanonical(t *testing.T) {
+	manager := newUserStorageManager(t)
+	projectPath := t.TempDir()
+	link := filepath.Join(t.TempDir(), "link")
+	if err := os.Symlink(projectPath, link); err != nil {
+		t.Skipf("symlinks not supported: %v", err)
+	}
+
+	assert.Equal(t, manager.ProjectConfigPath(projectPath), manager.ProjectConfigPath(link))
+	assert.NotEqual(t, manager.ProjectConfigPath(projectPath), manager.ProjectConfigPath(t.TempDir()))
+}
+
+func TestConfigManager_UserStorageMigration(t *testing.T) {
+	manager := newUserStorageManager(t)
+	projectPath := t.TempDir()
+
+	legacyFile := filepath.Join(projectPath, ProjectConfigFileName)
+	require.NoError(t, os.WriteFile(legacyFile, []byte(`{"run_command": "npm test"}`), 0644))
+
+	loaded := manager.LoadProjectConfig(projectPath)
+	assert.Equal(t, "npm test", loaded.RunCommand)
+
+	_, err := os.Stat(legacyFile)
+	assert.True(t, os.IsNotExist(err))
+	_, err = os.Stat(manager.ProjectConfigPath(projectPath))
+	assert.NoError(t, err)
+}
+
+func TestNewConfigManager_InvalidStorage(t *testing.T) {
+	isolateUserDirs(t)
+
+	manager, err := NewConfigManager()
+	require.NoError(t, err)
+	require.NoError(t, manager.SaveUserConfig(&types.UserConfig{ConfigStorage: "cloud"}))
+
+	// An unknown storage falls back to the project rather than failing
+	manager, err = NewConfigManager()
+	require.NoError(t, err)
+	assert.Equal(t, StorageProject, manager.Storage())
+}
+
+func TestNewConfigManager_InvalidUserConfig(t *testing.T) {
+	isolateUserDirs(t)
+
+	manager, err := NewConfigManager()
+	require.NoError(t, err)
+	require.NoError(t, os.MkdirAll(manager.UserConfigDir(), 0755))
+	require.NoError(t, os.WriteFile(manager.UserConfigPath(), []byte("{broken"), 0644))
+
+	// The manager still works, so the file can be repaired through it
+	manager, err = NewConfigManager()
+	require.NoError(t, err)
+	assert.Equal(t, StorageProject, manager.Storage())
+	require.NoError(t, manager.SaveUserConfig(&types.UserConfig{ConfigStorage: StorageProject}))
+	_, err = manager.LoadUserConfig()
+	assert.NoError(t, err)
+}
@@ -0,0 +1,67 @@
+package config
+
+import (
+	"os"
+	"sync"
+	"time"
+
+	"interactive-feedback-mcp/internal/types"
+)
+
+// ConfigWatcher polls a project config file and reports changes on disk
+type ConfigWatcher struct {
+	stop     chan struct{}
+	stopOnce sync.Once
+	done     chan struct{}
+}
+
+// WatchProjectConfig calls onChange with the reloaded config whenever the
+// project config file is created, modified or removed.
+func (cm *ConfigManager) WatchProjectConfig(projectPath string, interval time.Duration, onChange func(*types.ProjectConfig)) *ConfigWatcher {
+	watcher := &ConfigWatcher{
+		stop: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+
+	configFile := cm.ProjectConfigPath(projectPath)
+	lastModified := modificationTime(configFile)
+
+	go func() {
+		defer close(watcher.done)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-watcher.stop:
+				return
+			case <-ticker.C:
+				modified := modificationTime(configFile)
+				if modified.Equal(lastModified) {
+					continue
+				}
+				lastModified = modified
+				onChange(cm.LoadProjectConfig(projectPath))
+			}
+		}
+	}()
+
+	return watcher
+}
+
+// Stop ends polling and waits for the watcher goroutine to exit
+func (w *ConfigWatcher) Stop() {
+	w.stopOnce.Do(func() {
+		close(w.stop)
+	})
+	<-w.done
+}
+
+func modificationTime(path string) time.Time {
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}
+	}
+	return info.ModTime()
+}
@@ -0,0 +1,40 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"interactive-feedback-mcp/internal/types"
+)
+
+func TestConfigManager_WatchProjectConfig(t *testing.T) {
+	isolateUserDirs(t)
+	manager, err := NewConfigManager()
+	require.NoError(t, err)
+
+	projectPath := t.TempDir()
+	changes := make(chan *types.ProjectConfig, 1)
+
+	watcher := manager.WatchProjectConfig(projectPath, 10*time.Millisecond, func(config *types.ProjectConfig) {
+		// The watcher may report more than once; the test only needs the first
+		select {
+		case changes <- config:
+		default:
+		}
+	})
+	defer watcher.Stop()
+
+	err = manager.SaveProjectConfig(projectPath, &types.ProjectConfig{
+		DisabledTools: []string{"interactive_feedback"},
+	})
+	require.NoError(t, err)
+
+	select {
+	case config := <-changes:
+		assert.Equal(t, []string{"interactive_feedback"}, config.DisabledTools)
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timeout waiting for config change")
+	}
+}
@@ -0,0 +1,214 @@
+package conversation
+
+import (
+	"fmt"
+	"strings"
+
+	"interactive-feedback-mcp/internal/plan"
+	"interactive-feedback-mcp/internal/types"
+)
+
+// Text renders the body of an entry as plain text. Entries without a
+// payload, or with one of an unknown kind, render as their Content.
+func Text(entry types.ConversationEntry) string {
+	switch payload := entry.Payload.(type) {
+	case *types.ChoicePayload:
+		var text strings.Builder
+		if payload.Question != "" {
+			text.WriteString(payload.Question + "\n")
+		}
+		fmt.Fprintf(&text, "Selected: %s", strings.Join(payload.Selected, ", "))
+		if len(payload.Options) > 0 {
+			fmt.Fprintf(&text, " (options: %s)", strings.Join(payload.Options, ", "))
+		}
+		return text.String()
+
+	case *types.FormPayload:
+		var lines []string
+		if payload.Title != "" {
+			lines = append(lines, payload.Title)
+		}
+		for _, field := range payload.Fields {
+			lines = append(lines, fmt.Sprintf("%s: %s", fieldLabel(field), field.Value))
+		}
+		return strings.Join(lines, "\n")
+
+	case *types.CommandResultPayload:
+		lines := []string{"$ " + payload.Command}
+		if output := strings.TrimRight(payload.Output, "\n"); output != "" {
+			lines = append(lines, output)
+		}
+		return strings.Join(append(lines, commandStatus(payload)), "\n")
+
+	case *types.AttachmentPayload:
+		var lines []string
+		if entry.Content != "" {
+			lines = append(lines, entry.Content)
+		}
+		for _, attachment := range payload.Attachments {
+			lines = append(lines, fmt.Sprintf("Attached: %s (%s, %s)", attachment.Name, attachment.MimeType, FormatSize(attachment.Size)))
+		}
+		return strings.Join(lines, "\n")
+
+	case *types.SystemPayload:
+		if entry.Content != "" {
+			return entry.Content
+		}
+		return payload.Event
+
+	case *types.Plan:
+		return plan.Summary(*payload)
+	}
+	return entry.Content
+}
+
+// Markdown renders the body of an entry as Markdown. Text entries are
+// assumed to be Markdown already and are returned as is.
+func Markdown(entry types.ConversationEntry) string {
+	switch payload := entry.Payload.(type) {
+	case *types.ChoicePayload:
+		var text strings.Builder
+		if payload.Question != "" {
+			fmt.Fprintf(&text, "**%s**\n\n", payload.Question)
+		}
+		options := payload.Options
+		if len(options) == 0 {
+			options = payload.Selected
+		}
+		for _, option := range options {
+			fmt.Fprintf(&text, "- [%s] %s\n", checkMark(contains(payload.Selected, option)), option)
+		}
+		return strings.TrimSuffix(text.String(), "\n")
+
+	case *types.FormPayload:
+		var text strings.Builder
+		if payload.Title != "" {
+			fmt.Fprintf(&text, "**%s**\n\n", payload.Title)
+		}
+		for _, field := range payload.Fields {
+			fmt.Fprintf(&text, "- **%s**: %s\n", fieldLabel(field), field.Value)
+		}
+		return strings.TrimSuffix(text.String(), "\n")
+
+	case *types.CommandResultPayload:
+		text := fmt.Sprintf("`$ %s` — %s", payload.Command, commandStatus(payload))
+		if output := strings.TrimRight(payload.Output, "\n"); output != "" {
+			fence := CodeFence(output)
+			text += fmt.Sprintf("\n\n%s\n%s\n%s", fence, output, fence)
+		}
+		return text
+
+	case *types.AttachmentPayload:
+		var text strings.Builder
+		if entry.Content != "" {
+			text.WriteString(entry.Content + "\n\n")
+		}
+		for _, attachment := range payload.Attachments {
+			fmt.Fprintf(&text, "- 📎 `%s` (%s, %s)\n", attachment.Name, attachment.MimeType, FormatSize(attachment.Size))
+		}
+		return strings.TrimSuffix(text.String(), "\n")
+
+	case *types.SystemPayload:
+		return "_" + Text(entry) + "_"
+
+	case *types.Plan:
+		var text strings.Builder
+		if payload.Title != "" {
+			fmt.Fprintf(&text, "**Plan: %s** (%s)\n\n", payload.Title, payload.Verdict)
+		} else {
+			fmt.Fprintf(&text, "**Plan** (%s)\n\n", payload.Verdict)
+		}
+		for i, step := range payload.Steps {
+			var note string
+			switch {
+			case step.Original == nil:
+				note = " _(added)_"
+			case step.Edited:
+				note = " _(edited)_"
+			}
+			fmt.Fprintf(&text, "%d. [%s] %s%s\n", i+1, checkMark(step.Approved), step.Text, note)
+		}
+		for _, index := range payload.Removed {
+			fmt.Fprintf(&text, "\n~~%s~~ _(removed)_", payload.Proposed[index])
+		}
+		if payload.Comment != "" {
+			fmt.Fprintf(&text, "\n> %s", strings.ReplaceAll(payload.Comment, "\n", "\n> "))
+		}
+		return strings.TrimSuffix(text.String(), "\n")
+	}
+	return entry.Content
+}
+
+// CodeFence returns a backtick fence longer than any backtick run in text
+func CodeFence(text string) string {
+	longest, run := 0, 0
+	for _, r := range text {
+		if r == '`' {
+			run++
+			if run > longest {
+				longest = run
+			}
+		} else {
+			run = 0
+		}
+	}
+	if longest < 3 {
+		return "```"
+	}
+	return strings.Repeat("`", longest+1)
+}
+
+// FormatSize renders a byte count for people
+func FormatSize(size int64) string {
+	switch {
+	case size < 1024:
+		return fmt.Sprintf("%d B", size)
+	case size < 1024*1024:
+		return fmt.Sprintf("%.1f KB", float64(size)/1024)
+	default:
+		return fmt.Sprintf("%.1f MB", float64(size)/(1024*1024))
+	}
+}
+
+func commandStatus(payload *types.CommandResultPayload) string {
+	var status string
+	switch {
+	case payload.Error != "":
+		return "failed to run: " + payload.Error
+	case payload.TimedOut:
+		status = "timed out"
+	case payload.Signal != "":
+		status = "killed by " + payload.Signal
+	case payload.ExitCode != nil:
+		status = fmt.Sprintf("exit code %d", *payload.ExitCode)
+	default:
+		status = "finished"
+	}
+	if payload.DurationMs > 0 {
+		status += fmt.Sprintf(" in %.1fs", float64(payload.DurationMs)/1000)
+	}
+	return status
+}
+
+func fieldLabel(field types.FormField) string {
+	if field.Label != "" {
+		return field.Label
+	}
+	return field.Name
+}
+
+func checkMark(checked bool) string {
+	if checked {
+		return "x"
+	}
+	return " "
+}
+
+func contains(values []string, value string) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,121 @@
+package conversation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"interactive-feedback-mcp/internal/types"
+)
+
+func intPointer(value int) *int {
+	return &value
+}
+
+func TestText(t *testing.T) {
+	tests := map[string]struct {
+		entry    types.ConversationEntry
+		expected string
+	}{
+		"text": {
+			types.ConversationEntry{Content: "Looks good"},
+			"Looks good",
+		},
+		"choice": {
+			types.ConversationEntry{Payload: &types.ChoicePayload{Question: "Which database?", Options: []string{"sqlite", "postgres"}, Selected: []string{"postgres"}}},
+			"Which database?\nSelected: postgres (options: sqlite, postgres)",
+		},
+		"form": {
+			types.ConversationEntry{Payload: &types.FormPayload{Title: "Release", Fields: []types.FormField{{Name: "version", Label: "Version", Value: "1.2.0"}, {Name: "notes", Value: "none"}}}},
+			"Release\nVersion: 1.2.0\nnotes: none",
+		},
+		"command result": {
+			types.ConversationEntry{Payload: &types.CommandResultPayload{Command: "go test ./...", ExitCode: intPointer(1), DurationMs: 1500, Output: "FAIL\n"}},
+			"$ go test ./...\nFAIL\nexit code 1 in 1.5s",
+		},
+		"command error": {
+			types.ConversationEntry{Payload: &types.CommandResultPayload{Command: "make", Error: "not approved"}},
+			"$ make\nfailed to run: not approved",
+		},
+		"attachment": {
+			types.ConversationEntry{Content: "See the screenshot", Payload: &types.AttachmentPayload{Attachments: []types.Attachment{{Name: "ui.png", MimeType: "image/png", Size: 2048}}}},
+			"See the screenshot\nAttached: ui.png (image/png, 2.0 KB)",
+		},
+		"system": {
+			types.ConversationEntry{Payload: &types.SystemPayload{Event: "history_cleared"}},
+			"history_cleared",
+		},
+		"plan": {
+			types.ConversationEntry{Payload: &types.Plan{Title: "Roles", Proposed: []string{"a"}, Steps: []types.PlanStep{{Text: "a", Original: intPointer(0), Approved: true}}, Verdict: types.VerdictApproved}},
+			"Plan: Roles (approved)\n1. [x] a",
+		},
+		"unknown kind": {
+			types.ConversationEntry{Content: "fallback", Payload: &types.UnknownPayload{Kind: "poll"}},
+			"fallback",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.expected, Text(test.entry))
+		})
+	}
+}
+
+func TestMarkdown(t *testing.T) {
+	tests := map[string]struct {
+		entry    types.ConversationEntry
+		expected string
+	}{
+		"text is already markdown": {
+			types.ConversationEntry{Content: "# Title\n\n```go\nx := 1\n```"},
+			"# Title\n\n```go\nx := 1\n```",
+		},
+		"choice": {
+			types.ConversationEntry{Payload: &types.ChoicePayload{Question: "Which database?", Options: []string{"sqlite", "postgres"}, Selected: []string{"postgres"}}},
+			"**Which database?**\n\n- [ ] sqlite\n- [x] postgres",
+		},
+		"form": {
+			types.ConversationEntry{Payload: &types.FormPayload{Fields: []types.FormField{{Name: "version", Value: "1.2.0"}}}},
+			"- **version**: 1.2.0",
+		},
+		"command result": {
+			types.ConversationEntry{Payload: &types.CommandResultPayload{Command: "go test", ExitCode: intPointer(0), Output: "ok\n"}},
+			"`$ go test` — exit code 0\n\n```\nok\n```",
+		},
+		"attachment": {
+			types.ConversationEntry{Payload: &types.AttachmentPayload{Attachments: []types.Attachment{{Name: "app.log", MimeType: "text/plain", Size: 3 * 1024 * 1024}}}},
+			"- 📎 `app.log` (text/plain, 3.0 MB)",
+		},
+		"system": {
+			types.ConversationEntry{Content: "History cleared", Payload: &types.SystemPayload{Event: "history_cleared"}},
+			"_History cleared_",
+		},
+		"plan": {
+			types.ConversationEntry{Payload: &types.Plan{
+				Proposed: []string{"a", "b"},
+				Steps:    []types.PlanStep{{Text: "a!", Original: intPointer(0), Edited: true, Approved: true}, {Text: "c"}},
+				Removed:  []int{1},
+				Verdict:  types.VerdictPartial,
+				Comment:  "ok",
+			}},
+			"**Plan** (partial)\n\n1. [x] a! _(edited)_\n2. [ ] c _(added)_\n\n~~b~~ _(removed)_\n> ok",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.expected, Markdown(test.entry))
+		})
+	}
+}
+
+func TestCodeFence(t *testing.T) {
+	assert.Equal(t, "```", CodeFence("plain"))
+	assert.Equal(t, "````", CodeFence("```go\nx\n```"))
+}
+
+func TestFormatSize(t *testing.T) {
+	assert.Equal(t, "512 B", FormatSize(512))
+	assert.Equal(t, "1.5 KB", FormatSize(1536))
+	assert.Equal(t, "2.0 MB", FormatSize(2*1024*1024))
+}
@@ -0,0 +1,47 @@
+package diff
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// emptyTree is the id of git's empty tree, used to diff repositories without commits
+const emptyTree = "4b825dc642cb6eb9a060e54bf8d69288fbee4904"
+
+// GitDiff returns the uncommitted changes to tracked files in the repository
+// containing dir, staged or not. Untracked files are not included.
+func GitDiff(dir string) (string, error) {
+	// Outside a repository git diff would fall back to comparing paths
+	if _, err := runGit(dir, "rev-parse", "--is-inside-work-tree"); err != nil {
+		return "", fmt.Errorf("%s is not inside a git repository", dir)
+	}
+
+	base := "HEAD"
+	if _, err := runGit(dir, "rev-parse", "--verify", "--quiet", "HEAD"); err != nil {
+		base = emptyTree
+	}
+
+	output, err := runGit(dir, "diff", "--no-color", "--no-ext-diff", base)
+	if err != nil {
+		return "", fmt.Errorf("failed to run git diff: %w", err)
+	}
+	return output, nil
+}
+
+func runGit(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		if message := strings.TrimSpace(stderr.String()); message != "" {
+			return "", fmt.Errorf("%w: %s", err, message)
+		}
+		return "", err
+	}
+	return string(output), nil
+}
@@ -0,0 +1,67 @@
+package diff
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func initRepo(t *testing.T) string {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	dir := t.TempDir()
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"config", "user.email", "test@example.com"},
+		{"config", "user.name", "Test"},
+	} {
+		_, err := runGit(dir, args...)
+		require.NoError(t, err)
+	}
+	return dir
+}
+
+func TestGitDiff(t *testing.T) {
+	dir := initRepo(t)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "a.txt"), []byte("one\ntwo\n"), 0644))
+	_, err := runGit(dir, "add", "a.txt")
+	require.NoError(t, err)
+
+	// Staged changes show up before the first commit
+	text, err := GitDiff(dir)
+	require.NoError(t, err)
+	files, err := Parse(text)
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	assert.Equal(t, "a.txt", files[0].Path())
+
+	_, err = runGit(dir, "commit", "-q", "-m", "initial")
+	require.NoError(t, err)
+	text, err = GitDiff(dir)
+	require.NoError(t, err)
+	assert.Empty(t, text)
+
+	// Unstaged edits to tracked files are included
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "a.txt"), []byte("one\n2\n"), 0644))
+	text, err = GitDiff(dir)
+	require.NoError(t, err)
+	files, err = Parse(text)
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	assert.Equal(t, []string{" one", "-two", "+2"}, files[0].Hunks[0].Lines)
+}
+
+func TestGitDiff_NotARepository(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	_, err := GitDiff(t.TempDir())
+	assert.Error(t, err)
+}
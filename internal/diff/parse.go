@@ -0,0 +1,200 @@
+package diff
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// File is the part of a unified diff that changes one file
+type File struct {
+	OldPath string `json:"old_path,omitempty"` // empty for a new file
+	NewPath string `json:"new_path,omitempty"` // empty for a deleted file
+	Binary  bool   `json:"binary,omitempty"`
+	Hunks   []Hunk `json:"hunks"`
+}
+
+// Hunk is one "@@" section of a file diff. Lines keep their leading ' ',
+// '+', '-' or '\' marker.
+type Hunk struct {
+	Header   string   `json:"header"`
+	OldStart int      `json:"old_start"`
+	OldLines int      `json:"old_lines"`
+	NewStart int      `json:"new_start"`
+	NewLines int      `json:"new_lines"`
+	Lines    []string `json:"lines"`
+}
+
+// Path is the path the file is known by: the new path, or the old one if the
+// file was deleted
+func (f File) Path() string {
+	if f.NewPath != "" {
+		return f.NewPath
+	}
+	return f.OldPath
+}
+
+// Parse splits a unified diff, as written by git diff or diff -u, into files
+// and hunks. Text before the first file header is ignored.
+func Parse(text string) ([]File, error) {
+	var files []File
+	var file *File
+	var hunk *Hunk
+	oldLeft, newLeft := 0, 0
+
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+	if n := len(lines); n > 0 && lines[n-1] == "" {
+		lines = lines[:n-1]
+	}
+
+	for number, line := range lines {
+		// Lines inside a hunk are taken as is until its counts are used up
+		if hunk != nil && (oldLeft > 0 || newLeft > 0 || strings.HasPrefix(line, `\`)) {
+			switch {
+			case strings.HasPrefix(line, "+"):
+				newLeft--
+			case strings.HasPrefix(line, "-"):
+				oldLeft--
+			case strings.HasPrefix(line, " "), line == "":
+				oldLeft--
+				newLeft--
+			case strings.HasPrefix(line, `\`): // "\ No newline at end of file"
+			default:
+				return nil, fmt.Errorf("line %d: unexpected %q inside hunk %s", number+1, line, hunk.Header)
+			}
+			if line == "" {
+				line = " " // Some tools strip the space of empty context lines
+			}
+			hunk.Lines = append(hunk.Lines, line)
+			if oldLeft < 0 || newLeft < 0 {
+				return nil, fmt.Errorf("line %d: hunk %s is longer than its header says", number+1, hunk.Header)
+			}
+			continue
+		}
+		hunk = nil
+
+		switch {
+		case strings.HasPrefix(line, "diff --git "):
+			files = append(files, File{})
+			file = &files[len(files)-1]
+			file.OldPath, file.NewPath = gitHeaderPaths(strings.TrimPrefix(line, "diff --git "))
+		case strings.HasPrefix(line, "--- "):
+			if file == nil || len(file.Hunks) > 0 {
+				files = append(files, File{})
+				file = &files[len(files)-1]
+			}
+			file.OldPath = headerPath(strings.TrimPrefix(line, "--- "), "a/")
+		case strings.HasPrefix(line, "+++ "):
+			if file == nil {
+				return nil, fmt.Errorf("line %d: +++ without a preceding --- line", number+1)
+			}
+			file.NewPath = headerPath(strings.TrimPrefix(line, "+++ "), "b/")
+		case strings.HasPrefix(line, "@@"):
+			if file == nil {
+				return nil, fmt.Errorf("line %d: hunk before any file header", number+1)
+			}
+			parsed, err := parseHunkHeader(line)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", number+1, err)
+			}
+			file.Hunks = append(file.Hunks, parsed)
+			hunk = &file.Hunks[len(file.Hunks)-1]
+			oldLeft, newLeft = parsed.OldLines, parsed.NewLines
+		case strings.HasPrefix(line, "new file mode"):
+			if file != nil {
+				file.OldPath = ""
+			}
+		case strings.HasPrefix(line, "deleted file mode"):
+			if file != nil {
+				file.NewPath = ""
+			}
+		case strings.HasPrefix(line, "Binary files ") || line == "GIT binary patch":
+			if file != nil {
+				file.Binary = true
+			}
+		}
+	}
+
+	if hunk != nil && (oldLeft > 0 || newLeft > 0) {
+		return nil, fmt.Errorf("hunk %s is truncated", hunk.Header)
+	}
+	return files, nil
+}
+
+// parseHunkHeader reads "@@ -oldStart,oldLines +newStart,newLines @@ section"
+func parseHunkHeader(line string) (Hunk, error) {
+	fields := strings.Fields(line)
+	if len(fields) < 4 || fields[0] != "@@" || fields[3] != "@@" {
+		return Hunk{}, fmt.Errorf("invalid hunk header %q", line)
+	}
+
+	oldStart, oldLines, err := parseRange(fields[1], "-")
+	if err != nil {
+		return Hunk{}, fmt.Errorf("invalid hunk header %q: %w", line, err)
+	}
+	newStart, newLines, err := parseRange(fields[2], "+")
+	if err != nil {
+		return Hunk{}, fmt.Errorf("invalid hunk header %q: %w", line, err)
+	}
+
+	return Hunk{
+		Header:   line,
+		OldStart: oldStart,
+		OldLines: oldLines,
+		NewStart: newStart,
+		NewLines: newLines,
+	}, nil
+}
+
+// parseRange reads "-start,count" or "-start", where a missing count means 1
+func parseRange(field, sign string) (int, int, error) {
+	if !strings.HasPrefix(field, sign) {
+		return 0, 0, fmt.Errorf("range %q does not start with %s", field, sign)
+	}
+
+	start, count, found := strings.Cut(strings.TrimPrefix(field, sign), ",")
+	startValue, err := strconv.Atoi(start)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid range %q", field)
+	}
+	countValue := 1
+	if found {
+		if countValue, err = strconv.Atoi(count); err != nil {
+			return 0, 0, fmt.Errorf("invalid range %q", field)
+		}
+	}
+	return startValue, countValue, nil
+}
+
+// headerPath reads the path of a ---/+++ line, which may be followed by a
+// tab and a timestamp; /dev/null becomes an empty path
+func headerPath(value, prefix string) string {
+	path, _, _ := strings.Cut(value, "\t")
+	path = unquote(strings.TrimSpace(path))
+	if path == "/dev/null" {
+		return ""
+	}
+	return strings.TrimPrefix(path, prefix)
+}
+
+// gitHeaderPaths reads "a/old b/new" from a diff --git line. Paths with
+// spaces are ambiguous there, so the ---/+++ lines take precedence when present.
+func gitHeaderPaths(value string) (string, string) {
+	if strings.HasPrefix(value, `"`) {
+		return "", ""
+	}
+	if index := strings.Index(value, " b/"); index >= 0 {
+		return strings.TrimPrefix(value[:index], "a/"), value[index+3:]
+	}
+	return "", ""
+}
+
+// unquote decodes the C-style quoting git uses for unusual paths
+func unquote(path string) string {
+	if len(path) >= 2 && strings.HasPrefix(path, `"`) && strings.HasSuffix(path, `"`) {
+		if unquoted, err := strconv.Unquote(path); err == nil {
+			return unquoted
+		}
+	}
+	return path
+}
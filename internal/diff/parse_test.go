@@ -0,0 +1,124 @@
+package diff
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const gitDiff = `diff --git a/main.go b/main.go
+index 3b18e51..a4c2b1d 100644
+--- a/main.go
++++ b/main.go
+@@ -1,3 +1,4 @@ package main
+ import "fmt"
+ 
++// main prints a greeting
+ func main() {
+@@ -10,3 +11,2 @@ func helper() {
+ 	a := 1
+-	b := 2
+ 	return
+diff --git a/notes.txt b/notes.txt
+new file mode 100644
+index 0000000..e69de29
+--- /dev/null
++++ b/notes.txt
+@@ -0,0 +1 @@
++hello
+\ No newline at end of file
+diff --git a/old.txt b/old.txt
+deleted file mode 100644
+index e69de29..0000000
+--- a/old.txt
++++ /dev/null
+@@ -1 +0,0 @@
+-bye
+diff --git a/logo.png b/logo.png
+index 1111111..2222222 100644
+Binary files a/logo.png and b/logo.png differ
+`
+
+func TestParse_GitDiff(t *testing.T) {
+	files, err := Parse(gitDiff)
+	require.NoError(t, err)
+	require.Len(t, files, 4)
+
+	main := files[0]
+	assert.Equal(t, "main.go", main.OldPath)
+	assert.Equal(t, "main.go", main.NewPath)
+	require.Len(t, main.Hunks, 2)
+	assert.Equal(t, Hunk{
+		Header:   "@@ -1,3 +1,4 @@ package main",
+		OldStart: 1, OldLines: 3, NewStart: 1, NewLines: 4,
+		Lines: []string{` import "fmt"`, " ", "+// main prints a greeting", " func main() {"},
+	}, main.Hunks[0])
+	assert.Equal(t, []string{" \ta := 1", "-\tb := 2", " \treturn"}, main.Hunks[1].Lines)
+
+	notes := files[1]
+	assert.Empty(t, notes.OldPath)
+	assert.Equal(t, "notes.txt", notes.Path())
+	require.Len(t, notes.Hunks, 1)
+	assert.Equal(t, 1, notes.Hunks[0].NewLines)
+	assert.Equal(t, []string{"+hello", `\ No newline at end of file`}, notes.Hunks[0].Lines)
+
+	deleted := files[2]
+	assert.Empty(t, deleted.NewPath)
+	assert.Equal(t, "old.txt", deleted.Path())
+
+	assert.True(t, files[3].Binary)
+	assert.Empty(t, files[3].Hunks)
+}
+
+func TestParse_PlainUnifiedDiff(t *testing.T) {
+	text := "--- a.txt\t2024-01-01 10:00:00\n+++ a.txt\t2024-01-02 10:00:00\n@@ -1,2 +1,2 @@\n-one\n+uno\n two\n" +
+		"--- b.txt\n+++ b.txt\n@@ -3 +3 @@\n-x\n+y\n"
+
+	files, err := Parse(text)
+	require.NoError(t, err)
+	require.Len(t, files, 2)
+	assert.Equal(t, "a.txt", files[0].Path())
+	assert.Equal(t, "b.txt", files[1].Path())
+	assert.Equal(t, 3, files[1].Hunks[0].OldStart)
+	assert.Equal(t, 1, files[1].Hunks[0].OldLines)
+}
+
+func TestParse_LineThatLooksLikeHeaderInsideHunk(t *testing.T) {
+	text := "--- a.md\n+++ a.md\n@@ -1 +1 @@\n--- old rule\n+++ new rule\n"
+
+	files, err := Parse(text)
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	assert.Equal(t, []string{"--- old rule", "+++ new rule"}, files[0].Hunks[0].Lines)
+}
+
+func TestParse_QuotedPath(t *testing.T) {
+	text := "diff --git \"a/with space.txt\" \"b/with space.txt\"\n--- \"a/with space.txt\"\n+++ \"b/with space.txt\"\n@@ -1 +1 @@\n-a\n+b\n"
+
+	files, err := Parse(text)
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	assert.Equal(t, "with space.txt", files[0].Path())
+}
+
+func TestParse_Errors(t *testing.T) {
+	tests := map[string]string{
+		"hunk before file": "@@ -1 +1 @@\n-a\n+b\n",
+		"bad header":       "--- a\n+++ a\n@@ -x +1 @@\n",
+		"truncated":        "--- a\n+++ a\n@@ -1,3 +1,3 @@\n a\n",
+		"junk in hunk":     "--- a\n+++ a\n@@ -1,2 +1,2 @@\n a\n*b\n",
+	}
+	for name, text := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := Parse(text)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestParse_Empty(t *testing.T) {
+	files, err := Parse("")
+	require.NoError(t, err)
+	assert.Empty(t, files)
+}
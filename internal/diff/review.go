@@ -0,0 +1,75 @@
+package diff
+
+import (
+	"fmt"
+
+	"interactive-feedback-mcp/internal/types"
+)
+
+// Decisions is what the review window returns: a verdict and comment per
+// hunk and an overall comment
+type Decisions struct {
+	Hunks   []types.HunkReview `json:"hunks"`
+	Comment string             `json:"comment"`
+}
+
+// Summarize turns the user's decisions into the verdict for the reviewed
+// files. Hunks without a decision are pending; decisions for hunks that are
+// not part of the diff are an error.
+func Summarize(files []File, decisions Decisions) (types.DiffReviewResult, error) {
+	type hunkKey struct {
+		file string
+		hunk int
+	}
+	decided := make(map[hunkKey]types.HunkReview, len(decisions.Hunks))
+	for _, decision := range decisions.Hunks {
+		switch decision.Verdict {
+		case types.VerdictApproved, types.VerdictRejected, types.VerdictPending:
+		default:
+			return types.DiffReviewResult{}, fmt.Errorf("invalid verdict %q for %s hunk %d", decision.Verdict, decision.File, decision.Hunk)
+		}
+		decided[hunkKey{decision.File, decision.Hunk}] = decision
+	}
+
+	result := types.DiffReviewResult{
+		Comment:       decisions.Comment,
+		ApprovedHunks: []types.HunkReview{},
+		RejectedHunks: []types.HunkReview{},
+	}
+	for _, file := range files {
+		for index, hunk := range file.Hunks {
+			key := hunkKey{file.Path(), index}
+			review := types.HunkReview{File: file.Path(), Hunk: index, Header: hunk.Header, Verdict: types.VerdictPending}
+			if decision, ok := decided[key]; ok {
+				review.Verdict = decision.Verdict
+				review.Comment = decision.Comment
+				delete(decided, key)
+			}
+
+			switch review.Verdict {
+			case types.VerdictApproved:
+				result.ApprovedHunks = append(result.ApprovedHunks, review)
+			case types.VerdictRejected:
+				result.RejectedHunks = append(result.RejectedHunks, review)
+			default:
+				result.PendingHunks = append(result.PendingHunks, review)
+			}
+		}
+	}
+	for key := range decided {
+		return types.DiffReviewResult{}, fmt.Errorf("%s has no hunk %d", key.file, key.hunk)
+	}
+
+	approved, rejected := len(result.ApprovedHunks), len(result.RejectedHunks)
+	switch {
+	case approved > 0 && rejected == 0 && len(result.PendingHunks) == 0:
+		result.Verdict = types.VerdictApproved
+	case approved > 0:
+		result.Verdict = types.VerdictPartial
+	case rejected > 0:
+		result.Verdict = types.VerdictRejected
+	default:
+		result.Verdict = types.VerdictPending
+	}
+	return result, nil
+}
@@ -0,0 +1,70 @@
+package diff
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"interactive-feedback-mcp/internal/types"
+)
+
+func reviewFiles() []File {
+	return []File{
+		{NewPath: "a.go", Hunks: []Hunk{{Header: "@@ -1 +1 @@"}, {Header: "@@ -9 +9 @@"}}},
+		{OldPath: "b.go", Hunks: []Hunk{{Header: "@@ -1 +0,0 @@"}}},
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	result, err := Summarize(reviewFiles(), Decisions{
+		Comment: "Almost there",
+		Hunks: []types.HunkReview{
+			{File: "a.go", Hunk: 0, Verdict: types.VerdictApproved},
+			{File: "a.go", Hunk: 1, Verdict: types.VerdictRejected, Comment: "Keep this check"},
+		},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, types.VerdictPartial, result.Verdict)
+	assert.Equal(t, "Almost there", result.Comment)
+	assert.Equal(t, []types.HunkReview{{File: "a.go", Hunk: 0, Header: "@@ -1 +1 @@", Verdict: types.VerdictApproved}}, result.ApprovedHunks)
+	assert.Equal(t, []types.HunkReview{{File: "a.go", Hunk: 1, Header: "@@ -9 +9 @@", Verdict: types.VerdictRejected, Comment: "Keep this check"}}, result.RejectedHunks)
+	assert.Equal(t, []types.HunkReview{{File: "b.go", Hunk: 0, Header: "@@ -1 +0,0 @@", Verdict: types.VerdictPending}}, result.PendingHunks)
+}
+
+func TestSummarize_Verdicts(t *testing.T) {
+	all := func(verdict string) Decisions {
+		return Decisions{Hunks: []types.HunkReview{
+			{File: "a.go", Hunk: 0, Verdict: verdict},
+			{File: "a.go", Hunk: 1, Verdict: verdict},
+			{File: "b.go", Hunk: 0, Verdict: verdict},
+		}}
+	}
+
+	tests := map[string]struct {
+		decisions Decisions
+		verdict   string
+	}{
+		"all approved": {all(types.VerdictApproved), types.VerdictApproved},
+		"all rejected": {all(types.VerdictRejected), types.VerdictRejected},
+		"undecided":    {Decisions{}, types.VerdictPending},
+		"rejected and pending": {Decisions{Hunks: []types.HunkReview{
+			{File: "b.go", Hunk: 0, Verdict: types.VerdictRejected},
+		}}, types.VerdictRejected},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			result, err := Summarize(reviewFiles(), test.decisions)
+			require.NoError(t, err)
+			assert.Equal(t, test.verdict, result.Verdict)
+		})
+	}
+}
+
+func TestSummarize_RejectsUnknownHunks(t *testing.T) {
+	_, err := Summarize(reviewFiles(), Decisions{Hunks: []types.HunkReview{{File: "a.go", Hunk: 5, Verdict: types.VerdictApproved}}})
+	assert.Error(t, err)
+
+	_, err = Summarize(reviewFiles(), Decisions{Hunks: []types.HunkReview{{File: "a.go", Hunk: 0, Verdict: "maybe"}}})
+	assert.Error(t, err)
+}
@@ -0,0 +1,240 @@
+package executor
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ansiColorNames are the eight basic ANSI colors, in SGR order
+var ansiColorNames = []string{"black", "red", "green", "yellow", "blue", "magenta", "cyan", "white"}
+
+// Style is the text style selected by ANSI SGR sequences. Colors are a basic
+// color name ("red", "bright-red") or "#rrggbb"; empty means the default.
+type Style struct {
+	Foreground string
+	Background string
+	Bold       bool
+	Italic     bool
+	Underline  bool
+}
+
+// Segment is a run of text sharing one style
+type Segment struct {
+	Text  string
+	Style Style
+}
+
+// StripANSI removes ANSI escape sequences from terminal output
+func StripANSI(text string) string {
+	var plain strings.Builder
+	for _, segment := range ParseANSI(text) {
+		plain.WriteString(segment.Text)
+	}
+	return plain.String()
+}
+
+// PlainText turns raw terminal output into plain text: escape sequences are
+// removed, CRLF line endings become LF, and a line redrawn with carriage
+// returns (e.g. a progress bar) keeps only what was drawn last.
+func PlainText(text string) string {
+	lines := strings.Split(strings.ReplaceAll(StripANSI(text), "\r\n", "\n"), "\n")
+	for i, line := range lines {
+		lines[i] = overwriteLine(line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// overwriteLine applies carriage returns within a single line
+func overwriteLine(line string) string {
+	if !strings.Contains(line, "\r") {
+		return line
+	}
+
+	var screen []rune
+	for _, part := range strings.Split(line, "\r") {
+		drawn := []rune(part)
+		if len(drawn) >= len(screen) {
+			screen = drawn
+		} else {
+			copy(screen, drawn)
+		}
+	}
+	return string(screen)
+}
+
+// ParseANSI splits terminal output into styled segments. SGR sequences set
+// the style; other escape sequences are dropped.
+func ParseANSI(text string) []Segment {
+	var segments []Segment
+	var current strings.Builder
+	var style Style
+
+	flush := func() {
+		if current.Len() == 0 {
+			return
+		}
+		if n := len(segments); n > 0 && segments[n-1].Style == style {
+			segments[n-1].Text += current.String()
+		} else {
+			segments = append(segments, Segment{Text: current.String(), Style: style})
+		}
+		current.Reset()
+	}
+
+	for i := 0; i < len(text); {
+		if text[i] != 0x1b {
+			current.WriteByte(text[i])
+			i++
+			continue
+		}
+
+		if i+1 >= len(text) {
+			break // Dangling escape at the end of the output
+		}
+		switch text[i+1] {
+		case '[': // CSI: parameters, then a final byte in 0x40-0x7e
+			end := i + 2
+			for end < len(text) && (text[end] < 0x40 || text[end] > 0x7e) {
+				end++
+			}
+			if end >= len(text) {
+				i = len(text)
+				continue
+			}
+			if text[end] == 'm' {
+				flush()
+				style = applySGR(style, text[i+2:end])
+			}
+			i = end + 1
+		case ']': // OSC: terminated by BEL or ESC \
+			end := i + 2
+			for end < len(text) && text[end] != 0x07 && !(text[end] == 0x1b && end+1 < len(text) && text[end+1] == '\\') {
+				end++
+			}
+			switch {
+			case end >= len(text):
+				i = len(text)
+			case text[end] == 0x07:
+				i = end + 1
+			default:
+				i = end + 2
+			}
+		default: // Two-byte escape such as ESC = or ESC 7
+			i += 2
+		}
+	}
+	flush()
+
+	return segments
+}
+
+// applySGR updates a style with the parameters of a Select Graphic Rendition sequence
+func applySGR(style Style, params string) Style {
+	if params == "" {
+		return Style{}
+	}
+
+	codes := strings.FieldsFunc(params, func(r rune) bool { return r == ';' || r == ':' })
+	for i := 0; i < len(codes); i++ {
+		code, err := strconv.Atoi(codes[i])
+		if err != nil {
+			continue
+		}
+
+		switch {
+		case code == 0:
+			style = Style{}
+		case code == 1:
+			style.Bold = true
+		case code == 3:
+			style.Italic = true
+		case code == 4:
+			style.Underline = true
+		case code == 22:
+			style.Bold = false
+		case code == 23:
+			style.Italic = false
+		case code == 24:
+			style.Underline = false
+		case code >= 30 && code <= 37:
+			style.Foreground = ansiColorNames[code-30]
+		case code == 39:
+			style.Foreground = ""
+		case code >= 40 && code <= 47:
+			style.Background = ansiColorNames[code-40]
+		case code == 49:
+			style.Background = ""
+		case code >= 90 && code <= 97:
+			style.Foreground = "bright-" + ansiColorNames[code-90]
+		case code >= 100 && code <= 107:
+			style.Background = "bright-" + ansiColorNames[code-100]
+		case code == 38 || code == 48:
+			color, used := extendedColor(codes[i+1:])
+			i += used
+			if code == 38 {
+				style.Foreground = color
+			} else {
+				style.Background = color
+			}
+		}
+	}
+	return style
+}
+
+// extendedColor parses the arguments of a 38/48 SGR code ("5;n" or "2;r;g;b")
+// and reports how many parameters it consumed
+func extendedColor(args []string) (string, int) {
+	if len(args) == 0 {
+		return "", 0
+	}
+
+	values := make([]int, len(args))
+	for i, arg := range args {
+		values[i], _ = strconv.Atoi(arg)
+	}
+
+	switch values[0] {
+	case 5:
+		if len(values) < 2 {
+			return "", len(values)
+		}
+		return paletteColor(values[1]), 2
+	case 2:
+		if len(values) < 4 {
+			return "", len(values)
+		}
+		return fmt.Sprintf("#%02x%02x%02x", clampByte(values[1]), clampByte(values[2]), clampByte(values[3])), 4
+	default:
+		return "", 1
+	}
+}
+
+// paletteColor converts an xterm 256-color index to a color
+func paletteColor(index int) string {
+	switch {
+	case index < 0 || index > 255:
+		return ""
+	case index < 8:
+		return ansiColorNames[index]
+	case index < 16:
+		return "bright-" + ansiColorNames[index-8]
+	case index < 232:
+		levels := []int{0, 95, 135, 175, 215, 255}
+		index -= 16
+		return fmt.Sprintf("#%02x%02x%02x", levels[index/36], levels[index/6%6], levels[index%6])
+	default:
+		gray := 8 + (index-232)*10
+		return fmt.Sprintf("#%02x%02x%02x", gray, gray, gray)
+	}
+}
+
+func clampByte(value int) int {
+	if value < 0 {
+		return 0
+	}
+	if value > 255 {
+		return 255
+	}
+	return value
+}
@@ -0,0 +1,52 @@
+package executor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStripANSI(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"plain", "hello\n", "hello\n"},
+		{"colors", "\x1b[1;32mok\x1b[0m done", "ok done"},
+		{"cursor movement", "a\x1b[2Kb\x1b[1Ac", "abc"},
+		{"osc title", "\x1b]0;title\x07text\x1b]2;x\x1b\\", "text"},
+		{"two byte escape", "\x1b=keypad", "keypad"},
+		{"truncated sequence", "text\x1b[3", "text"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, StripANSI(tt.input))
+		})
+	}
+}
+
+func TestPlainText(t *testing.T) {
+	assert.Equal(t, "line\nnext\n", PlainText("line\r\nnext\r\n"))
+	assert.Equal(t, "100% done\n", PlainText(" 10%\r 50%\r100% done\n"))
+	assert.Equal(t, "abcd", PlainText("xbcd\ra"))
+}
+
+func TestParseANSI(t *testing.T) {
+	segments := ParseANSI("plain \x1b[1;31mbold red\x1b[22m red \x1b[4;38;5;208morange\x1b[0m \x1b[48;2;1;2;3mbg\x1b[m")
+
+	assert.Equal(t, []Segment{
+		{Text: "plain "},
+		{Text: "bold red", Style: Style{Foreground: "red", Bold: true}},
+		{Text: " red ", Style: Style{Foreground: "red"}},
+		{Text: "orange", Style: Style{Foreground: "#ff8700", Underline: true}},
+		{Text: " "},
+		{Text: "bg", Style: Style{Background: "#010203"}},
+	}, segments)
+}
+
+func TestParseANSI_MergesEqualStyles(t *testing.T) {
+	segments := ParseANSI("\x1b[92ma\x1b[92mb\x1b[Kc")
+	assert.Equal(t, []Segment{{Text: "abc", Style: Style{Foreground: "bright-green"}}}, segments)
+}
@@ -0,0 +1,192 @@
+package executor
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"interactive-feedback-mcp/internal/types"
+)
+
+// DefaultBufferChunks bounds the number of chunks kept per command for tailing
+const DefaultBufferChunks = 10000
+
+// maxChunkBytes bounds a single chunk; longer lines are split into partial chunks
+const maxChunkBytes = 64 * 1024
+
+// outputBuffer is a bounded ring of output chunks shared by both streams.
+// Appending never blocks: once the ring is full the oldest chunks are
+// evicted, and readers that fell behind get a marker for what they missed.
+type outputBuffer struct {
+	mutex   sync.Mutex
+	changed *sync.Cond
+	chunks  []types.OutputChunk
+	first   int // ring index of the oldest chunk
+	count   int
+	nextSeq uint64
+	closed  bool
+
+	capture   *outputCapture // receives every chunk, in order, for CommandResult.Output
+	formatter textFormatter
+}
+
+func newOutputBuffer(capacity int, capture *outputCapture) *outputBuffer {
+	if capacity < 1 {
+		capacity = 1
+	}
+	buffer := &outputBuffer{
+		chunks:  make([]types.OutputChunk, capacity),
+		capture: capture,
+	}
+	buffer.changed = sync.NewCond(&buffer.mutex)
+	return buffer
+}
+
+// append records a chunk and assigns it the next sequence number
+func (b *outputBuffer) append(stream, data string, partial bool) types.OutputChunk {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	chunk := types.OutputChunk{Seq: b.nextSeq, Stream: stream, Data: data, Partial: partial}
+	b.nextSeq++
+
+	if b.count == len(b.chunks) {
+		b.first = (b.first + 1) % len(b.chunks)
+		b.count--
+	}
+	b.chunks[(b.first+b.count)%len(b.chunks)] = chunk
+	b.count++
+
+	if b.capture != nil {
+		b.capture.write(b.formatter.format(chunk))
+	}
+	b.changed.Broadcast()
+	return chunk
+}
+
+// close marks the end of the output and wakes up waiting readers
+func (b *outputBuffer) close() {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.closed = true
+	b.changed.Broadcast()
+}
+
+// Since implements types.OutputSource
+func (b *outputBuffer) Since(seq uint64) []types.OutputChunk {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	return b.since(seq)
+}
+
+// next blocks until chunks from seq on are available or the output has
+// ended. It reports false once no more chunks will follow.
+func (b *outputBuffer) next(seq uint64) ([]types.OutputChunk, bool) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	for seq >= b.nextSeq && !b.closed {
+		b.changed.Wait()
+	}
+	chunks := b.since(seq)
+	return chunks, !b.closed
+}
+
+func (b *outputBuffer) since(seq uint64) []types.OutputChunk {
+	oldest := b.nextSeq - uint64(b.count)
+	if seq >= b.nextSeq {
+		return nil
+	}
+
+	chunks := make([]types.OutputChunk, 0, b.nextSeq-seq+1)
+	if seq < oldest {
+		chunks = append(chunks, types.OutputChunk{
+			Seq:    seq,
+			Stream: types.StreamSystem,
+			Data:   fmt.Sprintf("[%d lines dropped]\n", oldest-seq),
+		})
+		seq = oldest
+	}
+	for ; seq < b.nextSeq; seq++ {
+		chunks = append(chunks, b.chunks[(b.first+int(seq-oldest))%len(b.chunks)])
+	}
+	return chunks
+}
+
+// readStream copies a pipe into the buffer. Lines longer than maxChunkBytes
+// and a final line without a newline are recorded as partial chunks.
+func readStream(pipe io.Reader, stream string, buffer *outputBuffer) {
+	reader := bufio.NewReaderSize(pipe, maxChunkBytes)
+	for {
+		line, err := reader.ReadSlice('\n')
+		switch {
+		case err == nil:
+			buffer.append(stream, string(line), false)
+		case err == bufio.ErrBufferFull:
+			buffer.append(stream, string(line), true)
+		default:
+			if len(line) > 0 {
+				buffer.append(stream, string(line), true)
+			}
+			return
+		}
+	}
+}
+
+// pumpOutput feeds the text form of the buffer to output, blocking on slow
+// consumers instead of dropping. Chunks evicted meanwhile show up as a marker.
+func pumpOutput(buffer *outputBuffer, output chan<- string) {
+	defer close(output)
+
+	var formatter textFormatter
+	var seq uint64
+	for {
+		chunks, more := buffer.next(seq)
+		for _, chunk := range chunks {
+			output <- formatter.format(chunk)
+			seq = chunk.Seq + 1
+		}
+		if !more {
+			return
+		}
+	}
+}
+
+// FormatChunks renders chunks read from a types.OutputSource as text, in the
+// same form as CommandHandle.Output
+func FormatChunks(chunks []types.OutputChunk) string {
+	var formatter textFormatter
+	var text strings.Builder
+	for _, chunk := range chunks {
+		text.WriteString(formatter.format(chunk))
+	}
+	return text.String()
+}
+
+// textFormatter renders chunks as plain text: stderr lines get an "[ERROR] "
+// prefix, and a line left open by one stream is ended before another's output.
+type textFormatter struct {
+	openStream string // stream whose last chunk was partial
+}
+
+func (f *textFormatter) format(chunk types.OutputChunk) string {
+	var text strings.Builder
+	if f.openStream != "" && f.openStream != chunk.Stream {
+		text.WriteString("\n")
+		f.openStream = ""
+	}
+	if f.openStream == "" && chunk.Stream == types.StreamStderr {
+		text.WriteString("[ERROR] ")
+	}
+	text.WriteString(chunk.Data)
+
+	f.openStream = ""
+	if chunk.Partial {
+		f.openStream = chunk.Stream
+	}
+	return text.String()
+}
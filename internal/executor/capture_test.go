@@ -0,0 +1,102 @@
+package executor
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"interactive-feedback-mcp/internal/types"
+)
+
+func TestOutputBuffer_Since(t *testing.T) {
+	buffer := newOutputBuffer(10, nil)
+	buffer.append(types.StreamStdout, "one\n", false)
+	buffer.append(types.StreamStderr, "two\n", false)
+	buffer.append(types.StreamStdout, "three\n", false)
+
+	chunks := buffer.Since(1)
+	require.Len(t, chunks, 2)
+	assert.Equal(t, types.OutputChunk{Seq: 1, Stream: types.StreamStderr, Data: "two\n"}, chunks[0])
+	assert.Equal(t, uint64(2), chunks[1].Seq)
+
+	assert.Empty(t, buffer.Since(3))
+}
+
+func TestOutputBuffer_DroppedMarker(t *testing.T) {
+	buffer := newOutputBuffer(3, nil)
+	for i := 0; i < 5; i++ {
+		buffer.append(types.StreamStdout, "line\n", false)
+	}
+
+	chunks := buffer.Since(0)
+	require.Len(t, chunks, 4)
+	assert.Equal(t, types.StreamSystem, chunks[0].Stream)
+	assert.Equal(t, "[2 lines dropped]\n", chunks[0].Data)
+	assert.Equal(t, uint64(2), chunks[1].Seq)
+	assert.Equal(t, uint64(4), chunks[3].Seq)
+}
+
+func TestOutputBuffer_CaptureKeepsEverything(t *testing.T) {
+	capture := &outputCapture{limit: DefaultMaxCaptureBytes}
+	buffer := newOutputBuffer(1, capture)
+	buffer.append(types.StreamStdout, "a\n", false)
+	buffer.append(types.StreamStderr, "b\n", false)
+
+	assert.Len(t, buffer.Since(0), 2) // marker plus the last chunk
+	assert.Equal(t, "a\n[ERROR] b\n", capture.output.String())
+}
+
+func TestReadStream_LongAndPartialLines(t *testing.T) {
+	long := strings.Repeat("x", maxChunkBytes+10)
+	buffer := newOutputBuffer(10, nil)
+	readStream(strings.NewReader(long+"\nend"), types.StreamStdout, buffer)
+
+	chunks := buffer.Since(0)
+	require.Len(t, chunks, 3)
+	assert.True(t, chunks[0].Partial)
+	assert.False(t, chunks[1].Partial)
+	assert.Equal(t, long+"\n", chunks[0].Data+chunks[1].Data)
+	assert.Equal(t, "end", chunks[2].Data)
+	assert.True(t, chunks[2].Partial)
+}
+
+func TestPumpOutput_DeliversMarkerToSlowReader(t *testing.T) {
+	buffer := newOutputBuffer(2, nil)
+	for i := 0; i < 4; i++ {
+		buffer.append(types.StreamStdout, "line\n", false)
+	}
+	buffer.close()
+
+	output := make(chan string)
+	go pumpOutput(buffer, output)
+
+	var received []string
+	for text := range output {
+		received = append(received, text)
+	}
+	assert.Equal(t, []string{"[2 lines dropped]\n", "line\n", "line\n"}, received)
+}
+
+func TestTextFormatter(t *testing.T) {
+	var formatter textFormatter
+	var text strings.Builder
+	for _, chunk := range []types.OutputChunk{
+		{Stream: types.StreamStderr, Data: "warn", Partial: true},
+		{Stream: types.StreamStderr, Data: "ing\n"},
+		{Stream: types.StreamStdout, Data: "progress", Partial: true},
+		{Stream: types.StreamStderr, Data: "oops\n"},
+	} {
+		text.WriteString(formatter.format(chunk))
+	}
+	assert.Equal(t, "[ERROR] warning\nprogress\n[ERROR] oops\n", text.String())
+}
+
+func TestFormatChunks(t *testing.T) {
+	buffer := newOutputBuffer(10, nil)
+	buffer.append(types.StreamStdout, "building", true)
+	buffer.append(types.StreamStderr, "warning\n", false)
+	buffer.append(types.StreamStdout, "done\n", false)
+
+	assert.Equal(t, "building\n[ERROR] warning\ndone\n", FormatChunks(buffer.Since(0)))
+}
@@ -1,100 +1,228 @@
 package executor
 
 import (
-	"bufio"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
-	"runtime"
 	"sync"
-	"syscall"
 	"time"
 
-	"github.com/shirou/gopsutil/v3/process"
 	"interactive-feedback-mcp/internal/types"
 )
 
 type CommandExecutor struct {
-	processes map[int]*types.CommandHandle
+	processes map[int]*runningCommand
 	mutex     sync.RWMutex
 }
 
 func NewCommandExecutor() *CommandExecutor {
 	return &CommandExecutor{
-		processes: make(map[int]*types.CommandHandle),
+		processes: make(map[int]*runningCommand),
 	}
 }
 
-func (ce *CommandExecutor) ExecuteCommand(command, workingDir string) (*types.CommandHandle, error) {
-	ce.mutex.Lock()
-	defer ce.mutex.Unlock()
+// Options adjusts how a command is started
+type Options struct {
+	// UsePTY runs the command in a pseudo-terminal (Linux only) so that tools
+	// keep colors and progress output. Stdout and stderr arrive as one stream.
+	UsePTY bool
+	// Termination overrides DefaultTerminationPolicy for KillProcessTree
+	Termination *TerminationPolicy
+	// Timeout stops the command with the termination escalation; 0 means none
+	Timeout time.Duration
+	// MaxOutputBytes bounds CommandResult.Output; 0 means DefaultMaxCaptureBytes
+	MaxOutputBytes int
+	// Limits are applied with ulimit before the command runs (not on Windows)
+	Limits ResourceLimits
+	// ClearEnv starts the command with only the inherited variables named in
+	// EnvAllowlist, or DefaultEnvAllowlist if it is empty
+	ClearEnv     bool
+	EnvAllowlist []string
+	// Shell runs the command instead of bash (cmd on Windows); LoginShell
+	// makes it load the user's profile first
+	Shell      string
+	LoginShell bool
+	// Env is added to the environment after the variables from EnvFile,
+	// which is relative to the project directory
+	Env     map[string]string
+	EnvFile string
+	// Subdir is the directory to run in, relative to the project directory
+	Subdir string
+}
 
-	var cmd *exec.Cmd
-	if runtime.GOOS == "windows" {
-		cmd = exec.Command("cmd", "/c", command)
-	} else {
-		cmd = exec.Command("bash", "-c", command)
+// OptionsFromProject builds start options from a project's configuration
+func OptionsFromProject(projectConfig *types.ProjectConfig) Options {
+	opts := Options{
+		UsePTY:     projectConfig.UsePTY,
+		Shell:      projectConfig.Shell,
+		LoginShell: projectConfig.LoginShell,
+		Env:        projectConfig.Env,
+		EnvFile:    projectConfig.EnvFile,
+		Subdir:     projectConfig.WorkingDir,
 	}
 
-	cmd.Dir = workingDir
-	cmd.SysProcAttr = &syscall.SysProcAttr{
-		Setpgid: true, // Create process group for easier cleanup
+	if projectConfig.StopInterruptSeconds != nil || projectConfig.StopTerminateSeconds != nil {
+		policy := DefaultTerminationPolicy
+		if seconds := projectConfig.StopInterruptSeconds; seconds != nil {
+			policy.InterruptGrace = time.Duration(*seconds * float64(time.Second))
+		}
+		if seconds := projectConfig.StopTerminateSeconds; seconds != nil {
+			policy.TerminateGrace = time.Duration(*seconds * float64(time.Second))
+		}
+		opts.Termination = &policy
 	}
+	return opts
+}
 
-	stdout, err := cmd.StdoutPipe()
+// OptionsForProfile builds start options for one of the project's named
+// commands: its cwd replaces working_dir and its env extends the project's
+func OptionsForProfile(projectConfig *types.ProjectConfig, profile types.CommandProfile) Options {
+	opts := OptionsFromProject(projectConfig)
+	if profile.Cwd != "" {
+		opts.Subdir = profile.Cwd
+	}
+	if len(profile.Env) > 0 {
+		env := make(map[string]string, len(opts.Env)+len(profile.Env))
+		for name, value := range opts.Env {
+			env[name] = value
+		}
+		for name, value := range profile.Env {
+			env[name] = value
+		}
+		opts.Env = env
+	}
+	return opts
+}
+
+// Run executes a command and blocks until it finishes. Output is not
+// streamed; it is returned in the result, bounded by DefaultMaxCaptureBytes.
+func (ce *CommandExecutor) Run(command, workingDir string) (*types.CommandResult, error) {
+	return ce.RunWithOptions(command, workingDir, Options{})
+}
+
+// RunWithOptions is Run with explicit start options
+func (ce *CommandExecutor) RunWithOptions(command, workingDir string, opts Options) (*types.CommandResult, error) {
+	handle, err := ce.ExecuteCommandWithOptions(command, workingDir, opts)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create stdout pipe: %w", err)
+		return nil, err
 	}
 
-	stderr, err := cmd.StderrPipe()
+	for range handle.Output {
+		// Drain the stream; the result carries the captured output
+	}
+	<-handle.Done
+
+	return handle.Result, nil
+}
+
+// ExecuteCommand starts a command and streams its output on handle.Output,
+// which is closed once all output has been delivered. handle.Chunks keeps an
+// ordered, bounded copy for tailing. handle.Result is available once
+// handle.Done is closed.
+func (ce *CommandExecutor) ExecuteCommand(command, workingDir string) (*types.CommandHandle, error) {
+	return ce.ExecuteCommandWithOptions(command, workingDir, Options{})
+}
+
+// ExecuteCommandWithOptions is ExecuteCommand with explicit start options
+func (ce *CommandExecutor) ExecuteCommandWithOptions(command, workingDir string, opts Options) (*types.CommandHandle, error) {
+	ce.mutex.Lock()
+	defer ce.mutex.Unlock()
+
+	script, err := limitCommand(command, opts.Limits)
+	if err != nil {
+		return nil, err
+	}
+
+	cmd, environment, err := prepareCommand(script, workingDir, opts)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create stderr pipe: %w", err)
+		return nil, err
+	}
+
+	var stdout, stderr io.Reader
+	var terminal *os.File
+	if opts.UsePTY {
+		if terminal, err = startInPTY(cmd); err != nil {
+			return nil, err
+		}
+		stdout = terminal
+	} else {
+		setProcessGroup(cmd)
+
+		stdoutPipe, err := cmd.StdoutPipe()
+		if err != nil {
+			return nil, fmt.Errorf("failed to create stdout pipe: %w", err)
+		}
+
+		stderrPipe, err := cmd.StderrPipe()
+		if err != nil {
+			return nil, fmt.Errorf("failed to create stderr pipe: %w", err)
+		}
+
+		if err := cmd.Start(); err != nil {
+			return nil, fmt.Errorf("failed to start command: %w", err)
+		}
+		stdout, stderr = stdoutPipe, stderrPipe
 	}
 
-	if err := cmd.Start(); err != nil {
-		return nil, fmt.Errorf("failed to start command: %w", err)
+	capture := &outputCapture{limit: DefaultMaxCaptureBytes}
+	if opts.MaxOutputBytes > 0 {
+		capture.limit = opts.MaxOutputBytes
 	}
+	buffer := newOutputBuffer(DefaultBufferChunks, capture)
 
 	handle := &types.CommandHandle{
 		PID:       cmd.Process.Pid,
 		StartTime: time.Now(),
-		IsRunning: true,
 		Output:    make(chan string, 100), // Buffered channel for performance
 		Done:      make(chan error, 1),
+		Chunks:    buffer,
 	}
+	handle.SetRunning(true)
 
-	ce.processes[handle.PID] = handle
-
-	// Start goroutines for reading output
-	go ce.readOutput(stdout.(*os.File), handle.Output, false)
-	go ce.readOutput(stderr.(*os.File), handle.Output, true)
-	go ce.waitForCompletion(cmd, handle)
-
-	return handle, nil
-}
-
-func (ce *CommandExecutor) readOutput(pipe *os.File, output chan<- string, isError bool) {
-	scanner := bufio.NewScanner(pipe)
-	prefix := ""
-	if isError {
-		prefix = "[ERROR] "
+	running := &runningCommand{handle: handle, opts: opts, environment: environment}
+	ce.processes[handle.PID] = running
+	if opts.Timeout > 0 {
+		running.timer = time.AfterFunc(opts.Timeout, running.timeoutExpired)
 	}
 
-	for scanner.Scan() {
-		line := prefix + scanner.Text() + "\n"
-		select {
-		case output <- line:
-		default:
-			// Channel is full, skip this line to prevent blocking
+	// Start goroutines for reading output
+	// Wait must not run before both pipes are fully read
+	var readers sync.WaitGroup
+	readers.Add(1)
+	go func() {
+		defer readers.Done()
+		readStream(stdout, types.StreamStdout, buffer)
+		if terminal != nil {
+			terminal.Close()
 		}
+	}()
+	if stderr != nil {
+		readers.Add(1)
+		go func() {
+			defer readers.Done()
+			readStream(stderr, types.StreamStderr, buffer)
+		}()
 	}
+	go pumpOutput(buffer, handle.Output)
+	go ce.waitForCompletion(command, cmd, running, buffer, &readers)
+
+	return handle, nil
 }
 
-func (ce *CommandExecutor) waitForCompletion(cmd *exec.Cmd, handle *types.CommandHandle) {
+func (ce *CommandExecutor) waitForCompletion(command string, cmd *exec.Cmd, running *runningCommand, buffer *outputBuffer, readers *sync.WaitGroup) {
+	handle := running.handle
+	readers.Wait()
+	buffer.close()
 	err := cmd.Wait()
-	handle.IsRunning = false
+	if running.timer != nil {
+		running.timer.Stop()
+	}
+	handle.Result = buildResult(command, cmd, handle.StartTime, buffer.capture, err)
+	handle.Result.TerminatedBy, handle.Result.TimedOut = running.termination()
+	handle.Result.Environment = running.environment
+	handle.SetRunning(false)
 	handle.Done <- err
-	close(handle.Output)
 	close(handle.Done)
 
 	ce.mutex.Lock()
@@ -102,36 +230,60 @@ func (ce *CommandExecutor) waitForCompletion(cmd *exec.Cmd, handle *types.Comman
 	ce.mutex.Unlock()
 }
 
+// KillProcessTree stops a command and all of its children. It returns once
+// the first signal is sent; the escalation to SIGTERM and SIGKILL runs in the
+// background and ends when the process group is gone. Calling it again while
+// the command is still stopping sends SIGKILL right away.
 func (ce *CommandExecutor) KillProcessTree(pid int) error {
 	ce.mutex.Lock()
 	defer ce.mutex.Unlock()
 
-	handle, exists := ce.processes[pid]
+	running, exists := ce.processes[pid]
 	if !exists {
 		return fmt.Errorf("process %d not found", pid)
 	}
 
-	// Kill the process group (includes all child processes)
-	if runtime.GOOS != "windows" {
-		if err := syscall.Kill(-pid, syscall.SIGTERM); err != nil {
-			// If SIGTERM fails, try SIGKILL
-			syscall.Kill(-pid, syscall.SIGKILL)
-		}
-	} else {
-		// Windows: Use taskkill to kill process tree
-		cmd := exec.Command("taskkill", "/F", "/T", "/PID", fmt.Sprintf("%d", pid))
-		cmd.Run()
+	// IsRunning is cleared by waitForCompletion once the process has exited
+	running.startTermination()
+	return nil
+}
+
+// Shutdown stops every running command, waiting up to timeout for the
+// graceful escalation before killing whatever is left
+func (ce *CommandExecutor) Shutdown(timeout time.Duration) {
+	ce.terminateAll()
+	if ce.waitForAll(timeout) {
+		return
 	}
 
-	// Also try to kill using gopsutil for additional cleanup
-	if proc, err := process.NewProcess(int32(pid)); err == nil {
-		children, _ := proc.Children()
-		for _, child := range children {
-			child.Kill()
-		}
-		proc.Kill()
+	// A second request skips straight to SIGKILL
+	ce.terminateAll()
+	ce.waitForAll(killWait)
+}
+
+func (ce *CommandExecutor) terminateAll() {
+	ce.mutex.Lock()
+	defer ce.mutex.Unlock()
+
+	for _, running := range ce.processes {
+		running.startTermination()
 	}
+}
 
-	handle.IsRunning = false
-	return nil
+// waitForAll reports whether every command exited within timeout
+func (ce *CommandExecutor) waitForAll(timeout time.Duration) bool {
+	deadline := time.Now().Add(timeout)
+	for {
+		ce.mutex.RLock()
+		remaining := len(ce.processes)
+		ce.mutex.RUnlock()
+
+		if remaining == 0 {
+			return true
+		}
+		if time.Now().After(deadline) {
+			return false
+		}
+		time.Sleep(exitPollInterval)
+	}
 }
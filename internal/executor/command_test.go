@@ -31,7 +31,7 @@ func TestCommandExecutor_ExecuteCommand(t *testing.T) {
 	handle, err := executor.ExecuteCommand(command, ".")
 	require.NoError(t, err)
 	assert.NotNil(t, handle)
-	assert.True(t, handle.IsRunning)
+	assert.True(t, handle.IsRunning())
 	assert.NotNil(t, handle.Output)
 	assert.NotNil(t, handle.Done)
 
@@ -62,7 +62,7 @@ done:
 		t.Fatal("Timeout waiting for command completion")
 	}
 
-	assert.False(t, handle.IsRunning)
+	assert.False(t, handle.IsRunning())
 }
 
 func TestCommandExecutor_ExecuteCommand_InvalidCommand(t *testing.T) {
@@ -111,7 +111,7 @@ func TestCommandExecutor_KillProcessTree(t *testing.T) {
 		t.Fatal("Process was not killed")
 	}
 
-	assert.False(t, handle.IsRunning)
+	assert.False(t, handle.IsRunning())
 }
 
 func TestCommandExecutor_ConcurrentCommands(t *testing.T) {
@@ -142,3 +142,98 @@ func TestCommandExecutor_ConcurrentCommands(t *testing.T) {
 		}
 	}
 }
+
+func TestCommandExecutor_Run(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a POSIX shell")
+	}
+	executor := NewCommandExecutor()
+
+	result, err := executor.Run("echo out; echo err >&2; exit 3", ".")
+	require.NoError(t, err)
+	require.NotNil(t, result)
+
+	assert.Equal(t, 3, result.ExitCode)
+	assert.Empty(t, result.Signal)
+	assert.Contains(t, result.Output, "out\n")
+	assert.Contains(t, result.Output, "[ERROR] err\n")
+	assert.Equal(t, int64(len(result.Output)), result.BytesCaptured)
+	assert.False(t, result.Truncated)
+	assert.False(t, result.EndTime.Before(result.StartTime))
+	assert.Equal(t, result.EndTime.Sub(result.StartTime).Milliseconds(), result.DurationMs)
+}
+
+func TestCommandExecutor_Run_Signal(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("signals are not available on Windows")
+	}
+	executor := NewCommandExecutor()
+
+	result, err := executor.Run("kill -TERM $$", ".")
+	require.NoError(t, err)
+	assert.Equal(t, -1, result.ExitCode)
+	assert.Equal(t, "SIGTERM", result.Signal)
+}
+
+func TestCommandExecutor_ResultAfterKill(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("signals are not available on Windows")
+	}
+	executor := NewCommandExecutor()
+
+	handle, err := executor.ExecuteCommand("sleep 10", ".")
+	require.NoError(t, err)
+	require.NoError(t, executor.KillProcessTree(handle.PID))
+
+	select {
+	case <-handle.Done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Process was not killed")
+	}
+
+	require.NotNil(t, handle.Result)
+	assert.NotEmpty(t, handle.Result.Signal)
+}
+
+func TestOutputCapture_Truncation(t *testing.T) {
+	capture := &outputCapture{limit: 8}
+	capture.write("12345")
+	capture.write("67890")
+	capture.write("abc")
+
+	assert.Equal(t, "12345678", capture.output.String())
+	assert.Equal(t, int64(13), capture.bytes)
+	assert.True(t, capture.truncated)
+}
+
+func TestCommandExecutor_ExecuteCommand_SlowConsumer(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a POSIX shell loop")
+	}
+
+	executor := NewCommandExecutor()
+	handle, err := executor.ExecuteCommand("for i in $(seq 1 500); do echo line$i; done", ".")
+	require.NoError(t, err)
+
+	// Let the command finish before reading anything
+	<-handle.Done
+
+	var lines []string
+	for line := range handle.Output {
+		lines = append(lines, line)
+	}
+	require.Len(t, lines, 500)
+	assert.Equal(t, "line1\n", lines[0])
+	assert.Equal(t, "line500\n", lines[499])
+}
+
+func TestCommandExecutor_ExecuteCommand_Chunks(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses POSIX shell redirection")
+	}
+
+	executor := NewCommandExecutor()
+	result, err := executor.Run("echo out; sleep 0.1; echo err >&2; sleep 0.1; printf tail", ".")
+	require.NoError(t, err)
+	assert.Equal(t, "out\n[ERROR] err\ntail", result.Output)
+}
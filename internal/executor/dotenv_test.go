@@ -0,0 +1,66 @@
+package executor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseEnvFile(t *testing.T) {
+	vars, err := ParseEnvFile(`
+# comment
+PLAIN=value
+export EXPORTED=yes
+SPACED = padded value  # trailing comment
+SINGLE='literal $HOME \n'
+DOUBLE="line\nbreak \"quoted\""
+EMPTY=
+URL=http://example.com/#anchor
+`)
+	require.NoError(t, err)
+
+	assert.Equal(t, []EnvVar{
+		{Name: "PLAIN", Value: "value"},
+		{Name: "EXPORTED", Value: "yes"},
+		{Name: "SPACED", Value: "padded value"},
+		{Name: "SINGLE", Value: `literal $HOME \n`},
+		{Name: "DOUBLE", Value: "line\nbreak \"quoted\""},
+		{Name: "EMPTY", Value: ""},
+		{Name: "URL", Value: "http://example.com/#anchor"},
+	}, vars)
+}
+
+func TestParseEnvFile_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		message string
+	}{
+		{"missing equals", "A=1\nNOVALUE\n", "line 2: expected NAME=value"},
+		{"invalid name", "1ABC=x", "line 1: expected NAME=value"},
+		{"unterminated double quote", `A="open`, "line 1: unterminated double quote"},
+		{"unterminated single quote", `A='open`, "line 1: unterminated single quote"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseEnvFile(tt.content)
+			assert.EqualError(t, err, tt.message)
+		})
+	}
+}
+
+func TestLoadEnvFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".env")
+	require.NoError(t, os.WriteFile(path, []byte("KEY=value\n"), 0644))
+
+	vars, err := LoadEnvFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, []EnvVar{{Name: "KEY", Value: "value"}}, vars)
+
+	_, err = LoadEnvFile(filepath.Join(t.TempDir(), "missing"))
+	assert.Error(t, err)
+}
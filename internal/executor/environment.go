@@ -0,0 +1,142 @@
+package executor
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"interactive-feedback-mcp/internal/types"
+)
+
+// prepareCommand resolves the shell, working directory and environment for a
+// command started in projectDir
+func prepareCommand(script, projectDir string, opts Options) (*exec.Cmd, *types.CommandEnvironment, error) {
+	dir, err := resolveSubdir(projectDir, opts.Subdir)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	env := commandEnvironment(opts)
+	description := &types.CommandEnvironment{Cleared: opts.ClearEnv}
+
+	if env == nil && (opts.EnvFile != "" || len(opts.Env) > 0) {
+		env = os.Environ()
+	}
+
+	if opts.EnvFile != "" {
+		envFile := opts.EnvFile
+		if !filepath.IsAbs(envFile) {
+			envFile = filepath.Join(projectDir, envFile)
+		}
+		vars, err := LoadEnvFile(envFile)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, v := range vars {
+			env = append(env, v.Name+"="+v.Value)
+			description.FromFile = append(description.FromFile, v.Name)
+		}
+	}
+
+	if len(opts.Env) > 0 {
+		names := make([]string, 0, len(opts.Env))
+		for name := range opts.Env {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		description.Set = make(map[string]string, len(opts.Env))
+		for _, name := range names {
+			env = append(env, name+"="+opts.Env[name])
+			description.Set[name] = opts.Env[name]
+		}
+	}
+
+	cmd := shellCommand(opts.Shell, opts.LoginShell, script)
+	cmd.Dir = dir
+	cmd.Env = env
+
+	description.Shell = cmd.Args[:len(cmd.Args)-1]
+	description.WorkingDir = dir
+	if env == nil {
+		description.Path = os.Getenv("PATH")
+	} else {
+		description.Path, _ = lookupEnv(env, "PATH")
+	}
+
+	return cmd, description, nil
+}
+
+// resolveSubdir joins a working subdirectory onto the project directory,
+// refusing paths that leave the project
+func resolveSubdir(projectDir, subdir string) (string, error) {
+	if abs, err := filepath.Abs(projectDir); err == nil {
+		projectDir = abs
+	}
+	if subdir == "" {
+		return projectDir, nil
+	}
+
+	if filepath.IsAbs(subdir) {
+		return "", fmt.Errorf("working directory %q must be relative to the project", subdir)
+	}
+	dir := filepath.Join(projectDir, subdir)
+	if rel, err := filepath.Rel(projectDir, dir); err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("working directory %q is outside the project", subdir)
+	}
+
+	info, err := os.Stat(dir)
+	if err != nil {
+		return "", fmt.Errorf("working directory %q does not exist", subdir)
+	}
+	if !info.IsDir() {
+		return "", fmt.Errorf("working directory %q is not a directory", subdir)
+	}
+	return dir, nil
+}
+
+// LaunchSpec describes everything that decides how command runs in
+// projectDir: the command, then any shell, working directory and environment
+// settings, one per line. Approvals cover this text, so a changed setting or
+// env file needs approving again. The env file is represented by a hash of
+// its contents, as the spec is shown to the user and may hold secrets.
+func LaunchSpec(command, projectDir string, opts Options) string {
+	lines := []string{strings.TrimSpace(command)}
+	if opts.Shell != "" {
+		lines = append(lines, "shell: "+opts.Shell)
+	}
+	if opts.LoginShell {
+		lines = append(lines, "login shell: true")
+	}
+	if opts.Subdir != "" {
+		lines = append(lines, "working directory: "+opts.Subdir)
+	}
+
+	if opts.EnvFile != "" {
+		envFile := opts.EnvFile
+		if !filepath.IsAbs(envFile) {
+			envFile = filepath.Join(projectDir, envFile)
+		}
+		if data, err := os.ReadFile(envFile); err != nil {
+			lines = append(lines, "env file: "+opts.EnvFile+" (unreadable)")
+		} else {
+			sum := sha256.Sum256(data)
+			lines = append(lines, "env file: "+opts.EnvFile+" (sha256 "+hex.EncodeToString(sum[:8])+")")
+		}
+	}
+
+	names := make([]string, 0, len(opts.Env))
+	for name := range opts.Env {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		lines = append(lines, "env: "+name+"="+opts.Env[name])
+	}
+	return strings.Join(lines, "\n")
+}
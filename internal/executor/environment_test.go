@@ -0,0 +1,138 @@
+package executor
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"interactive-feedback-mcp/internal/types"
+)
+
+func TestCommandExecutor_ProjectEnvironment(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses POSIX parameter expansion")
+	}
+	t.Setenv("FEEDBACK_TEST_INHERITED", "inherited")
+
+	projectDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(projectDir, ".env"), []byte("FROM_FILE=file\nOVERRIDDEN=file\n"), 0644))
+	require.NoError(t, os.Mkdir(filepath.Join(projectDir, "web"), 0755))
+
+	executor := NewCommandExecutor()
+	result, err := executor.RunWithOptions(`echo "$FEEDBACK_TEST_INHERITED $FROM_FILE $OVERRIDDEN"; basename "$PWD"`, projectDir, Options{
+		Shell:   "sh",
+		EnvFile: ".env",
+		Env:     map[string]string{"OVERRIDDEN": "config"},
+		Subdir:  "web",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "inherited file config\nweb\n", result.Output)
+
+	require.NotNil(t, result.Environment)
+	assert.Equal(t, []string{"sh", "-c"}, result.Environment.Shell)
+	assert.Equal(t, filepath.Join(projectDir, "web"), result.Environment.WorkingDir)
+	assert.Equal(t, map[string]string{"OVERRIDDEN": "config"}, result.Environment.Set)
+	assert.Equal(t, []string{"FROM_FILE", "OVERRIDDEN"}, result.Environment.FromFile)
+	assert.Equal(t, os.Getenv("PATH"), result.Environment.Path)
+}
+
+func TestCommandExecutor_LoginShell(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a POSIX login shell")
+	}
+
+	executor := NewCommandExecutor()
+	result, err := executor.RunWithOptions("shopt -q login_shell && echo login", ".", Options{LoginShell: true})
+	require.NoError(t, err)
+	assert.Contains(t, result.Output, "login\n") // Profiles may print their own output
+	assert.Equal(t, []string{"bash", "-l", "-c"}, result.Environment.Shell)
+}
+
+func TestCommandExecutor_EnvironmentErrors(t *testing.T) {
+	projectDir := t.TempDir()
+	executor := NewCommandExecutor()
+
+	_, err := executor.ExecuteCommandWithOptions("echo", projectDir, Options{EnvFile: "missing.env"})
+	assert.ErrorContains(t, err, "failed to read env file")
+
+	_, err = executor.ExecuteCommandWithOptions("echo", projectDir, Options{Subdir: "../elsewhere"})
+	assert.ErrorContains(t, err, "outside the project")
+
+	_, err = executor.ExecuteCommandWithOptions("echo", projectDir, Options{Subdir: "missing"})
+	assert.ErrorContains(t, err, "does not exist")
+}
+
+func TestResolveSubdir(t *testing.T) {
+	projectDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(projectDir, "a", "b"), 0755))
+
+	dir, err := resolveSubdir(projectDir, "")
+	require.NoError(t, err)
+	assert.Equal(t, projectDir, dir)
+
+	dir, err = resolveSubdir(projectDir, "a/b/../b")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(projectDir, "a", "b"), dir)
+
+	_, err = resolveSubdir(projectDir, projectDir)
+	assert.ErrorContains(t, err, "must be relative")
+}
+
+func TestOptionsFromProject_Environment(t *testing.T) {
+	opts := OptionsFromProject(&types.ProjectConfig{
+		Shell:      "zsh",
+		LoginShell: true,
+		Env:        map[string]string{"A": "1"},
+		EnvFile:    ".env.local",
+		WorkingDir: "frontend",
+	})
+
+	assert.Equal(t, "zsh", opts.Shell)
+	assert.True(t, opts.LoginShell)
+	assert.Equal(t, map[string]string{"A": "1"}, opts.Env)
+	assert.Equal(t, ".env.local", opts.EnvFile)
+	assert.Equal(t, "frontend", opts.Subdir)
+}
+
+func TestOptionsForProfile(t *testing.T) {
+	projectConfig := &types.ProjectConfig{
+		Env:        map[string]string{"A": "project", "B": "project"},
+		WorkingDir: "app",
+	}
+
+	opts := OptionsForProfile(projectConfig, types.CommandProfile{Name: "test"})
+	assert.Equal(t, "app", opts.Subdir)
+	assert.Equal(t, projectConfig.Env, opts.Env)
+
+	opts = OptionsForProfile(projectConfig, types.CommandProfile{Cwd: "e2e", Env: map[string]string{"B": "profile"}})
+	assert.Equal(t, "e2e", opts.Subdir)
+	assert.Equal(t, map[string]string{"A": "project", "B": "profile"}, opts.Env)
+	assert.Equal(t, "project", projectConfig.Env["B"]) // The project config is not modified
+}
+
+func TestLaunchSpec(t *testing.T) {
+	projectDir := t.TempDir()
+	assert.Equal(t, "make test", LaunchSpec(" make test\n", projectDir, Options{}))
+
+	require.NoError(t, os.WriteFile(filepath.Join(projectDir, ".env"), []byte("TOKEN=abc\n"), 0600))
+	opts := Options{
+		Shell:      "zsh",
+		LoginShell: true,
+		Subdir:     "web",
+		EnvFile:    ".env",
+		Env:        map[string]string{"B": "2", "A": "1"},
+	}
+	spec := LaunchSpec("make test", projectDir, opts)
+	assert.Regexp(t, `^make test\nshell: zsh\nlogin shell: true\nworking directory: web\nenv file: \.env \(sha256 [0-9a-f]{16}\)\nenv: A=1\nenv: B=2$`, spec)
+	assert.NotContains(t, spec, "abc", "env file values are not shown")
+
+	// Changing the env file changes the spec
+	require.NoError(t, os.WriteFile(filepath.Join(projectDir, ".env"), []byte("BASH_ENV=./x.sh\n"), 0600))
+	assert.NotEqual(t, spec, LaunchSpec("make test", projectDir, opts))
+
+	require.NoError(t, os.Remove(filepath.Join(projectDir, ".env")))
+	assert.Contains(t, LaunchSpec("make test", projectDir, opts), "env file: .env (unreadable)")
+}
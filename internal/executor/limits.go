@@ -0,0 +1,94 @@
+package executor
+
+import (
+	"os"
+	"runtime"
+	"strings"
+	"time"
+
+	"interactive-feedback-mcp/internal/types"
+)
+
+// ResourceLimits are per-process limits applied to a command; zero values
+// mean no limit
+type ResourceLimits struct {
+	CPUSeconds  int
+	MemoryBytes int64
+	OpenFiles   int
+}
+
+func (l ResourceLimits) isZero() bool {
+	return l == ResourceLimits{}
+}
+
+// DefaultEnvAllowlist is kept when ClearEnv is set without an allow-list
+var DefaultEnvAllowlist = []string{
+	"PATH", "HOME", "USER", "LOGNAME", "SHELL", "LANG", "LC_ALL", "TERM", "TMPDIR",
+	"TEMP", "TMP", "SYSTEMROOT", "COMSPEC", "PATHEXT",
+}
+
+// WithLimits returns opts restricted by the user's command limits
+func (opts Options) WithLimits(limits *types.CommandLimits) Options {
+	if limits == nil {
+		return opts
+	}
+
+	if limits.TimeoutSeconds > 0 {
+		opts.Timeout = time.Duration(limits.TimeoutSeconds * float64(time.Second))
+	}
+	if limits.MaxOutputBytes > 0 {
+		opts.MaxOutputBytes = limits.MaxOutputBytes
+	}
+	opts.Limits = ResourceLimits{
+		CPUSeconds:  limits.CPUSeconds,
+		MemoryBytes: int64(limits.MemoryMB) << 20,
+		OpenFiles:   limits.OpenFiles,
+	}
+	opts.ClearEnv = limits.ClearEnv
+	opts.EnvAllowlist = limits.EnvAllowlist
+	return opts
+}
+
+// commandEnvironment returns the environment for a command, or nil to
+// inherit the server's environment unchanged
+func commandEnvironment(opts Options) []string {
+	if !opts.ClearEnv {
+		return nil
+	}
+
+	allowlist := opts.EnvAllowlist
+	if len(allowlist) == 0 {
+		allowlist = DefaultEnvAllowlist
+	}
+
+	env := []string{}
+	for _, entry := range os.Environ() {
+		name, _, _ := strings.Cut(entry, "=")
+		for _, allowed := range allowlist {
+			if envNameEqual(name, allowed) {
+				env = append(env, entry)
+				break
+			}
+		}
+	}
+	return env
+}
+
+// lookupEnv finds a variable in an environment list
+func lookupEnv(env []string, name string) (string, bool) {
+	for i := len(env) - 1; i >= 0; i-- {
+		key, value, _ := strings.Cut(env[i], "=")
+		if envNameEqual(key, name) {
+			return value, true
+		}
+	}
+	return "", false
+}
+
+// envNameEqual compares variable names, ignoring case on Windows
+func envNameEqual(a, b string) bool {
+	if runtime.GOOS == "windows" {
+		return strings.EqualFold(a, b)
+	}
+	return a == b
+}
@@ -0,0 +1,114 @@
+package executor
+
+import (
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"interactive-feedback-mcp/internal/types"
+)
+
+func TestCommandExecutor_Timeout(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses POSIX signals")
+	}
+
+	executor := NewCommandExecutor()
+	started := time.Now()
+	result, err := executor.RunWithOptions("sleep 10", ".", Options{Timeout: 200 * time.Millisecond})
+	require.NoError(t, err)
+
+	assert.Less(t, time.Since(started), 5*time.Second)
+	assert.True(t, result.TimedOut)
+	assert.Equal(t, StageInterrupt, result.TerminatedBy)
+}
+
+func TestCommandExecutor_TimeoutNotReached(t *testing.T) {
+	executor := NewCommandExecutor()
+	result, err := executor.RunWithOptions("echo fast", ".", Options{Timeout: time.Minute})
+	require.NoError(t, err)
+
+	assert.False(t, result.TimedOut)
+	assert.Empty(t, result.TerminatedBy)
+	assert.Equal(t, 0, result.ExitCode)
+}
+
+func TestCommandExecutor_MaxOutputBytes(t *testing.T) {
+	executor := NewCommandExecutor()
+	result, err := executor.RunWithOptions("echo 0123456789", ".", Options{MaxOutputBytes: 4})
+	require.NoError(t, err)
+
+	assert.Equal(t, "0123", result.Output)
+	assert.True(t, result.Truncated)
+	assert.Greater(t, result.BytesCaptured, int64(4))
+}
+
+func TestCommandExecutor_ResourceLimits(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("ulimit is not available on Windows")
+	}
+
+	executor := NewCommandExecutor()
+	result, err := executor.RunWithOptions("ulimit -n; ulimit -t", ".", Options{
+		Limits: ResourceLimits{OpenFiles: 64, CPUSeconds: 30},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "64\n30\n", result.Output)
+}
+
+func TestCommandExecutor_ClearEnv(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses POSIX parameter expansion")
+	}
+	t.Setenv("FEEDBACK_TEST_SECRET", "secret")
+	t.Setenv("FEEDBACK_TEST_ALLOWED", "allowed")
+
+	executor := NewCommandExecutor()
+	script := "echo ${FEEDBACK_TEST_SECRET:-unset} ${FEEDBACK_TEST_ALLOWED:-unset}"
+
+	result, err := executor.RunWithOptions(script, ".", Options{})
+	require.NoError(t, err)
+	assert.Equal(t, "secret allowed\n", result.Output)
+
+	result, err = executor.RunWithOptions(script, ".", Options{ClearEnv: true})
+	require.NoError(t, err)
+	assert.Equal(t, "unset unset\n", result.Output)
+
+	result, err = executor.RunWithOptions(script, ".", Options{ClearEnv: true, EnvAllowlist: []string{"FEEDBACK_TEST_ALLOWED"}})
+	require.NoError(t, err)
+	assert.Equal(t, "unset allowed\n", result.Output)
+}
+
+func TestCommandEnvironment_DefaultAllowlist(t *testing.T) {
+	t.Setenv("FEEDBACK_TEST_SECRET", "secret")
+
+	env := commandEnvironment(Options{ClearEnv: true})
+	_, found := lookupEnv(env, "FEEDBACK_TEST_SECRET")
+	assert.False(t, found)
+	for _, entry := range env {
+		name, _, _ := strings.Cut(entry, "=")
+		assert.Contains(t, DefaultEnvAllowlist, strings.ToUpper(name))
+	}
+
+	assert.Nil(t, commandEnvironment(Options{}))
+}
+
+func TestOptions_WithLimits(t *testing.T) {
+	opts := Options{UsePTY: true}
+	assert.Equal(t, opts, opts.WithLimits(nil))
+
+	limited := opts.WithLimits(&types.CommandLimits{
+		TimeoutSeconds: 1.5,
+		MaxOutputBytes: 100,
+		MemoryMB:       2,
+		ClearEnv:       true,
+	})
+	assert.True(t, limited.UsePTY)
+	assert.Equal(t, 1500*time.Millisecond, limited.Timeout)
+	assert.Equal(t, 100, limited.MaxOutputBytes)
+	assert.Equal(t, ResourceLimits{MemoryBytes: 2 << 20}, limited.Limits)
+	assert.True(t, limited.ClearEnv)
+}
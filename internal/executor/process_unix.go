@@ -0,0 +1,79 @@
+//go:build !windows
+
+package executor
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// shellCommand runs command with the given shell (bash by default); a login
+// shell reads the user's profile first
+func shellCommand(shell string, login bool, command string) *exec.Cmd {
+	if shell == "" {
+		shell = "bash"
+	}
+	if login {
+		return exec.Command(shell, "-l", "-c", command)
+	}
+	return exec.Command(shell, "-c", command)
+}
+
+// limitCommand prefixes the command with ulimit calls for the given limits
+func limitCommand(command string, limits ResourceLimits) (string, error) {
+	if limits.isZero() {
+		return command, nil
+	}
+
+	var args []string
+	if limits.CPUSeconds > 0 {
+		args = append(args, fmt.Sprintf("-t %d", limits.CPUSeconds))
+	}
+	if limits.MemoryBytes > 0 {
+		args = append(args, fmt.Sprintf("-v %d", limits.MemoryBytes>>10))
+	}
+	if limits.OpenFiles > 0 {
+		args = append(args, fmt.Sprintf("-n %d", limits.OpenFiles))
+	}
+
+	// Exit before running anything if a limit cannot be applied
+	return fmt.Sprintf("ulimit %s || exit 125\n%s", strings.Join(args, " "), command), nil
+}
+
+func setProcessGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{
+		Setpgid: true, // Create process group for easier cleanup
+	}
+}
+
+// stageSignals maps termination stages to signals
+var stageSignals = map[string]syscall.Signal{
+	StageInterrupt: syscall.SIGINT,
+	StageTerminate: syscall.SIGTERM,
+	StageKill:      syscall.SIGKILL,
+}
+
+// signalGroup sends the signal for a termination stage to the process group led by pid
+func signalGroup(pid int, stage string) error {
+	return syscall.Kill(-pid, stageSignals[stage])
+}
+
+// groupAlive reports whether any process of the group led by pid still exists
+func groupAlive(pid int) bool {
+	err := syscall.Kill(-pid, 0)
+	return err == nil || err == syscall.EPERM
+}
+
+// exitSignal names the signal that terminated the process, if any
+func exitSignal(state *os.ProcessState) string {
+	status, ok := state.Sys().(syscall.WaitStatus)
+	if !ok || !status.Signaled() {
+		return ""
+	}
+	return unix.SignalName(status.Signal())
+}
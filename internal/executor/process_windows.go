@@ -0,0 +1,62 @@
+//go:build windows
+
+package executor
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/shirou/gopsutil/v3/process"
+)
+
+// shellCommand runs command with the given shell (cmd by default). Only
+// PowerShell has a profile to load for login mode.
+func shellCommand(shell string, login bool, command string) *exec.Cmd {
+	if shell == "" {
+		shell = "cmd"
+	}
+
+	switch strings.ToLower(strings.TrimSuffix(filepath.Base(shell), ".exe")) {
+	case "powershell", "pwsh":
+		args := []string{"-NoLogo"}
+		if !login {
+			args = append(args, "-NoProfile")
+		}
+		return exec.Command(shell, append(args, "-Command", command)...)
+	default:
+		return exec.Command(shell, "/c", command)
+	}
+}
+
+// limitCommand fails when limits are requested; Windows has no ulimit
+func limitCommand(command string, limits ResourceLimits) (string, error) {
+	if limits.isZero() {
+		return command, nil
+	}
+	return "", fmt.Errorf("resource limits are not supported on Windows")
+}
+
+func setProcessGroup(cmd *exec.Cmd) {}
+
+// signalGroup asks the process tree to close; only the SIGKILL stage forces it
+func signalGroup(pid int, stage string) error {
+	args := []string{"/T", "/PID", fmt.Sprintf("%d", pid)}
+	if stage == StageKill {
+		args = append([]string{"/F"}, args...)
+	}
+	return exec.Command("taskkill", args...).Run()
+}
+
+// groupAlive reports whether the root process of the tree still exists
+func groupAlive(pid int) bool {
+	exists, err := process.PidExists(int32(pid))
+	return err == nil && exists
+}
+
+// exitSignal is always empty on Windows, which has no termination signals
+func exitSignal(state *os.ProcessState) string {
+	return ""
+}
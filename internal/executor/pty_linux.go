@@ -0,0 +1,75 @@
+//go:build linux
+
+package executor
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// Size of the pseudo-terminal given to commands
+const (
+	ptyRows = 24
+	ptyCols = 120
+)
+
+// openPTY allocates a pseudo-terminal pair through /dev/ptmx
+func openPTY() (*os.File, *os.File, error) {
+	master, err := os.OpenFile("/dev/ptmx", os.O_RDWR|syscall.O_NOCTTY|syscall.O_CLOEXEC, 0)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open /dev/ptmx: %w", err)
+	}
+
+	fd := int(master.Fd())
+	if err := unix.IoctlSetPointerInt(fd, unix.TIOCSPTLCK, 0); err != nil {
+		master.Close()
+		return nil, nil, fmt.Errorf("failed to unlock pty: %w", err)
+	}
+	index, err := unix.IoctlGetInt(fd, unix.TIOCGPTN)
+	if err != nil {
+		master.Close()
+		return nil, nil, fmt.Errorf("failed to get pty number: %w", err)
+	}
+	unix.IoctlSetWinsize(fd, unix.TIOCSWINSZ, &unix.Winsize{Row: ptyRows, Col: ptyCols})
+
+	slavePath := fmt.Sprintf("/dev/pts/%d", index)
+	slave, err := os.OpenFile(slavePath, os.O_RDWR|syscall.O_NOCTTY, 0)
+	if err != nil {
+		master.Close()
+		return nil, nil, fmt.Errorf("failed to open %s: %w", slavePath, err)
+	}
+	return master, slave, nil
+}
+
+// startInPTY starts cmd as a session leader whose controlling terminal is a
+// new pseudo-terminal. The returned master carries the combined output.
+func startInPTY(cmd *exec.Cmd) (*os.File, error) {
+	master, slave, err := openPTY()
+	if err != nil {
+		return nil, err
+	}
+	defer slave.Close() // The child keeps its own copies
+
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = slave, slave, slave
+	cmd.SysProcAttr = &syscall.SysProcAttr{
+		Setsid:  true, // Also makes the command a process group leader
+		Setctty: true,
+		Ctty:    0, // The child's stdin
+	}
+	if cmd.Env == nil {
+		cmd.Env = os.Environ()
+	}
+	if term, _ := lookupEnv(cmd.Env, "TERM"); term == "" || term == "dumb" {
+		cmd.Env = append(cmd.Env, "TERM=xterm-256color")
+	}
+
+	if err := cmd.Start(); err != nil {
+		master.Close()
+		return nil, fmt.Errorf("failed to start command: %w", err)
+	}
+	return master, nil
+}
@@ -0,0 +1,35 @@
+//go:build linux
+
+package executor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommandExecutor_RunWithOptions_PTY(t *testing.T) {
+	master, slave, err := openPTY()
+	if err != nil {
+		t.Skipf("pseudo-terminals are not available: %v", err)
+	}
+	master.Close()
+	slave.Close()
+
+	executor := NewCommandExecutor()
+	result, err := executor.RunWithOptions(`test -t 1 && printf '\033[31mtty\033[0m\n'; echo err >&2; exit 3`, ".", Options{UsePTY: true})
+	require.NoError(t, err)
+
+	assert.Equal(t, 3, result.ExitCode)
+	assert.Contains(t, result.Output, "\x1b[31mtty\x1b[0m\r\n")
+	assert.NotContains(t, result.Output, "[ERROR]") // A terminal has one output stream
+	assert.Equal(t, "tty\nerr\n", PlainText(result.Output))
+}
+
+func TestCommandExecutor_RunWithOptions_Pipes(t *testing.T) {
+	executor := NewCommandExecutor()
+	result, err := executor.RunWithOptions("test -t 1 || echo pipe", ".", Options{})
+	require.NoError(t, err)
+	assert.Equal(t, "pipe\n", result.Output)
+}
@@ -0,0 +1,14 @@
+//go:build !linux
+
+package executor
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+)
+
+// startInPTY is only implemented on Linux
+func startInPTY(cmd *exec.Cmd) (*os.File, error) {
+	return nil, fmt.Errorf("PTY mode is not supported on this platform")
+}
@@ -0,0 +1,65 @@
+package executor
+
+import (
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"interactive-feedback-mcp/internal/types"
+)
+
+// DefaultMaxCaptureBytes bounds the output kept in CommandResult.Output
+const DefaultMaxCaptureBytes = 1 << 20
+
+// outputCapture keeps a bounded copy of everything a command printed
+type outputCapture struct {
+	mutex     sync.Mutex
+	output    strings.Builder
+	bytes     int64
+	limit     int
+	truncated bool
+}
+
+func (c *outputCapture) write(text string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.bytes += int64(len(text))
+	if remaining := c.limit - c.output.Len(); remaining < len(text) {
+		c.truncated = true
+		if remaining > 0 {
+			c.output.WriteString(text[:remaining])
+		}
+		return
+	}
+	c.output.WriteString(text)
+}
+
+// buildResult describes a command that has exited (or failed to be waited on)
+func buildResult(command string, cmd *exec.Cmd, startTime time.Time, capture *outputCapture, waitErr error) *types.CommandResult {
+	endTime := time.Now()
+
+	capture.mutex.Lock()
+	result := &types.CommandResult{
+		Command:       command,
+		ExitCode:      -1,
+		StartTime:     startTime,
+		EndTime:       endTime,
+		DurationMs:    endTime.Sub(startTime).Milliseconds(),
+		BytesCaptured: capture.bytes,
+		Truncated:     capture.truncated,
+		Output:        capture.output.String(),
+	}
+	capture.mutex.Unlock()
+
+	if state := cmd.ProcessState; state != nil {
+		result.ExitCode = state.ExitCode()
+		result.Signal = exitSignal(state)
+	}
+	if waitErr != nil && cmd.ProcessState == nil {
+		result.Error = waitErr.Error()
+	}
+
+	return result
+}
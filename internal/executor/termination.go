@@ -0,0 +1,176 @@
+package executor
+
+import (
+	"sync"
+	"time"
+
+	"github.com/shirou/gopsutil/v3/process"
+	"interactive-feedback-mcp/internal/types"
+)
+
+// Termination stages, recorded in CommandResult.TerminatedBy
+const (
+	StageInterrupt = "SIGINT"
+	StageTerminate = "SIGTERM"
+	StageKill      = "SIGKILL"
+)
+
+// killWait bounds how long to wait for the group to disappear after SIGKILL
+const killWait = 2 * time.Second
+
+// exitPollInterval is how often termination checks whether the group is gone
+const exitPollInterval = 20 * time.Millisecond
+
+// TerminationPolicy controls how KillProcessTree escalates: SIGINT, then
+// SIGTERM after InterruptGrace, then SIGKILL after TerminateGrace. A zero
+// grace period skips that stage.
+type TerminationPolicy struct {
+	InterruptGrace time.Duration
+	TerminateGrace time.Duration
+}
+
+// DefaultTerminationPolicy gives commands a few seconds to clean up
+var DefaultTerminationPolicy = TerminationPolicy{
+	InterruptGrace: 2 * time.Second,
+	TerminateGrace: 3 * time.Second,
+}
+
+// runningCommand is a command tracked by the executor
+type runningCommand struct {
+	handle      *types.CommandHandle
+	opts        Options
+	timer       *time.Timer // enforces opts.Timeout
+	environment *types.CommandEnvironment
+
+	mutex        sync.Mutex
+	terminatedBy string // last stage sent
+	timedOut     bool
+	force        chan struct{} // closed to skip straight to SIGKILL; nil until termination starts
+}
+
+// startTermination begins the escalation, or forces it to SIGKILL if it is
+// already running
+func (rc *runningCommand) startTermination() {
+	rc.mutex.Lock()
+	defer rc.mutex.Unlock()
+
+	if rc.force != nil {
+		select {
+		case <-rc.force:
+		default:
+			close(rc.force)
+		}
+		return
+	}
+	rc.force = make(chan struct{})
+	go rc.escalate(rc.force)
+}
+
+// escalate signals the process group stage by stage until it is gone
+func (rc *runningCommand) escalate(force <-chan struct{}) {
+	pid := rc.handle.PID
+	policy := DefaultTerminationPolicy
+	if rc.opts.Termination != nil {
+		policy = *rc.opts.Termination
+	}
+
+	// Children that leave the group are only reachable through the process tree
+	descendants := descendantProcesses(pid)
+
+	stages := []struct {
+		name  string
+		grace time.Duration
+	}{
+		{StageInterrupt, policy.InterruptGrace},
+		{StageTerminate, policy.TerminateGrace},
+		{StageKill, killWait},
+	}
+	for _, stage := range stages {
+		forced := isClosed(force)
+		if stage.name != StageKill && (stage.grace <= 0 || forced) {
+			continue
+		}
+
+		rc.mutex.Lock()
+		rc.terminatedBy = stage.name
+		rc.mutex.Unlock()
+
+		signalGroup(pid, stage.name)
+		if stage.name == StageKill {
+			for _, child := range descendants {
+				child.Kill()
+			}
+			force = nil // Nothing left to skip
+		}
+
+		if waitForGroupExit(pid, stage.grace, force) {
+			return
+		}
+	}
+}
+
+// timeoutExpired stops a command that ran past its timeout
+func (rc *runningCommand) timeoutExpired() {
+	rc.mutex.Lock()
+	rc.timedOut = true
+	rc.mutex.Unlock()
+
+	rc.startTermination()
+}
+
+// termination returns the last termination stage sent, if any, and whether
+// the command was stopped because of its timeout
+func (rc *runningCommand) termination() (string, bool) {
+	rc.mutex.Lock()
+	defer rc.mutex.Unlock()
+
+	return rc.terminatedBy, rc.timedOut && rc.terminatedBy != ""
+}
+
+// waitForGroupExit polls until the process group is gone, the timeout
+// expires, or force is closed. It reports whether the group is gone.
+func waitForGroupExit(pid int, timeout time.Duration, force <-chan struct{}) bool {
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+	ticker := time.NewTicker(exitPollInterval)
+	defer ticker.Stop()
+
+	for {
+		if !groupAlive(pid) {
+			return true
+		}
+		select {
+		case <-ticker.C:
+		case <-deadline.C:
+			return !groupAlive(pid)
+		case <-force:
+			return false
+		}
+	}
+}
+
+// descendantProcesses lists every process below pid
+func descendantProcesses(pid int) []*process.Process {
+	root, err := process.NewProcess(int32(pid))
+	if err != nil {
+		return nil
+	}
+
+	var descendants []*process.Process
+	queue := []*process.Process{root}
+	for len(queue) > 0 {
+		children, _ := queue[0].Children()
+		queue = append(queue[1:], children...)
+		descendants = append(descendants, children...)
+	}
+	return descendants
+}
+
+func isClosed(ch <-chan struct{}) bool {
+	select {
+	case <-ch:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,143 @@
+//go:build !windows
+
+package executor
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"interactive-feedback-mcp/internal/types"
+)
+
+// fastTermination keeps the escalation tests quick
+var fastTermination = &TerminationPolicy{
+	InterruptGrace: 300 * time.Millisecond,
+	TerminateGrace: 300 * time.Millisecond,
+}
+
+// stopAndWait starts a trap script, waits for it to be ready and stops it
+func stopAndWait(t *testing.T, executor *CommandExecutor, script string, opts Options) (*types.CommandResult, string) {
+	t.Helper()
+
+	handle, err := executor.ExecuteCommandWithOptions(script, ".", opts)
+	require.NoError(t, err)
+
+	// Traps are installed before the script prints "ready"
+	var output strings.Builder
+	for line := range handle.Output {
+		output.WriteString(line)
+		if strings.Contains(line, "ready") {
+			break
+		}
+	}
+
+	require.NoError(t, executor.KillProcessTree(handle.PID))
+	for line := range handle.Output {
+		output.WriteString(line)
+	}
+
+	select {
+	case <-handle.Done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Process was not stopped")
+	}
+	return handle.Result, output.String()
+}
+
+func TestKillProcessTree_InterruptHandled(t *testing.T) {
+	executor := NewCommandExecutor()
+	result, output := stopAndWait(t, executor,
+		`trap 'echo cleanup; exit 0' INT; echo ready; while true; do sleep 0.05; done`,
+		Options{Termination: fastTermination})
+
+	assert.Contains(t, output, "cleanup")
+	assert.Equal(t, StageInterrupt, result.TerminatedBy)
+	assert.Equal(t, 0, result.ExitCode)
+}
+
+func TestKillProcessTree_EscalatesToTerminate(t *testing.T) {
+	executor := NewCommandExecutor()
+	result, _ := stopAndWait(t, executor,
+		`trap '' INT; echo ready; while true; do sleep 0.05; done`,
+		Options{Termination: fastTermination})
+
+	assert.Equal(t, StageTerminate, result.TerminatedBy)
+	assert.Equal(t, "SIGTERM", result.Signal)
+}
+
+func TestKillProcessTree_EscalatesToKill(t *testing.T) {
+	executor := NewCommandExecutor()
+	result, _ := stopAndWait(t, executor,
+		`trap '' INT TERM; echo ready; while true; do sleep 0.05; done`,
+		Options{Termination: fastTermination})
+
+	assert.Equal(t, StageKill, result.TerminatedBy)
+	assert.Equal(t, "SIGKILL", result.Signal)
+}
+
+func TestKillProcessTree_SecondCallForcesKill(t *testing.T) {
+	executor := NewCommandExecutor()
+	handle, err := executor.ExecuteCommandWithOptions(
+		`trap '' INT TERM; echo ready; while true; do sleep 0.05; done`, ".",
+		Options{Termination: &TerminationPolicy{InterruptGrace: time.Minute, TerminateGrace: time.Minute}})
+	require.NoError(t, err)
+	<-handle.Output
+
+	started := time.Now()
+	require.NoError(t, executor.KillProcessTree(handle.PID))
+	assert.True(t, handle.IsRunning(), "the command ignores SIGINT, so it is still running")
+	require.NoError(t, executor.KillProcessTree(handle.PID))
+
+	select {
+	case <-handle.Done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Process was not killed")
+	}
+	assert.Less(t, time.Since(started), 5*time.Second)
+	assert.Equal(t, StageKill, handle.Result.TerminatedBy)
+}
+
+func TestKillProcessTree_SkipsZeroGraceStages(t *testing.T) {
+	executor := NewCommandExecutor()
+	result, output := stopAndWait(t, executor,
+		`trap 'echo interrupted' INT; echo ready; while true; do sleep 0.05; done`,
+		Options{Termination: &TerminationPolicy{TerminateGrace: time.Second}})
+
+	assert.NotContains(t, output, "interrupted")
+	assert.Equal(t, StageTerminate, result.TerminatedBy)
+}
+
+func TestOptionsFromProject(t *testing.T) {
+	assert.Equal(t, Options{}, OptionsFromProject(&types.ProjectConfig{}))
+
+	zero, half := 0.0, 0.5
+	opts := OptionsFromProject(&types.ProjectConfig{UsePTY: true, StopInterruptSeconds: &zero, StopTerminateSeconds: &half})
+	assert.True(t, opts.UsePTY)
+	require.NotNil(t, opts.Termination)
+	assert.Equal(t, TerminationPolicy{InterruptGrace: 0, TerminateGrace: 500 * time.Millisecond}, *opts.Termination)
+}
+
+func TestCommandExecutor_Shutdown(t *testing.T) {
+	executor := NewCommandExecutor()
+
+	graceful, err := executor.ExecuteCommand("sleep 10", ".")
+	require.NoError(t, err)
+	stubborn, err := executor.ExecuteCommandWithOptions(
+		`trap '' INT TERM; echo ready; while true; do sleep 0.05; done`, ".",
+		Options{Termination: &TerminationPolicy{InterruptGrace: time.Minute, TerminateGrace: time.Minute}})
+	require.NoError(t, err)
+	<-stubborn.Output
+
+	started := time.Now()
+	executor.Shutdown(500 * time.Millisecond)
+	assert.Less(t, time.Since(started), 5*time.Second)
+
+	<-graceful.Done
+	<-stubborn.Done
+	assert.Equal(t, StageInterrupt, graceful.Result.TerminatedBy)
+	assert.Equal(t, StageKill, stubborn.Result.TerminatedBy)
+	assert.Empty(t, executor.processes)
+}
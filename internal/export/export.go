@@ -0,0 +1,120 @@
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"interactive-feedback-mcp/internal/conversation"
+	"interactive-feedback-mcp/internal/types"
+)
+
+// Export formats
+const (
+	FormatMarkdown = "markdown"
+	FormatJSON     = "json"
+	FormatText     = "text"
+)
+
+// Formats lists the export formats with their file extensions
+var Formats = map[string]string{
+	FormatMarkdown: ".md",
+	FormatJSON:     ".json",
+	FormatText:     ".txt",
+}
+
+// timestampLayout is used for entry times in Markdown and text exports
+const timestampLayout = "2006-01-02 15:04:05 MST"
+
+// Thread is a project's conversation as exported
+type Thread struct {
+	Project string                    `json:"project,omitempty"`
+	Entries []types.ConversationEntry `json:"entries"`
+}
+
+// Render exports the thread in the given format
+func Render(thread Thread, format string) (string, error) {
+	switch format {
+	case FormatMarkdown:
+		return Markdown(thread), nil
+	case FormatJSON:
+		return JSON(thread)
+	case FormatText:
+		return Text(thread), nil
+	}
+	return "", fmt.Errorf("unknown export format %q (use markdown, json or text)", format)
+}
+
+// FormatForPath picks the format from a file's extension, defaulting to Markdown
+func FormatForPath(path string) string {
+	extension := strings.ToLower(filepath.Ext(path))
+	for format, formatExtension := range Formats {
+		if extension == formatExtension {
+			return format
+		}
+	}
+	return FormatMarkdown
+}
+
+// Markdown renders the thread with a heading per entry. Text entries are
+// already Markdown, so their code fences are kept as written.
+func Markdown(thread Thread) string {
+	var text strings.Builder
+	if thread.Project != "" {
+		fmt.Fprintf(&text, "# Conversation: %s\n", thread.Project)
+	} else {
+		text.WriteString("# Conversation\n")
+	}
+	for _, entry := range thread.Entries {
+		fmt.Fprintf(&text, "\n## %s · %s\n\n", heading(entry), entry.Timestamp.Format(timestampLayout))
+		if body := conversation.Markdown(entry); body != "" {
+			text.WriteString(body + "\n")
+		}
+	}
+	return text.String()
+}
+
+// JSON renders the thread as indented JSON, entries keeping their kind and payload
+func JSON(thread Thread) (string, error) {
+	if thread.Entries == nil {
+		thread.Entries = []types.ConversationEntry{}
+	}
+	data, err := json.MarshalIndent(thread, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to export conversation: %w", err)
+	}
+	return string(data) + "\n", nil
+}
+
+// Text renders the thread as plain text, one block per entry
+func Text(thread Thread) string {
+	var blocks []string
+	for _, entry := range thread.Entries {
+		block := fmt.Sprintf("[%s] %s:", entry.Timestamp.Format(timestampLayout), heading(entry))
+		if body := conversation.Text(entry); body != "" {
+			block += "\n" + body
+		}
+		blocks = append(blocks, block)
+	}
+	if len(blocks) == 0 {
+		return ""
+	}
+	return strings.Join(blocks, "\n\n") + "\n"
+}
+
+// heading names the entry's role, and its kind unless it is plain text
+func heading(entry types.ConversationEntry) string {
+	role := entry.Role
+	if role != "" {
+		role = strings.ToUpper(role[:1]) + role[1:]
+	}
+	kind := entry.Kind
+	if kind == "" && entry.Payload != nil {
+		kind = entry.Payload.EntryKind()
+	}
+	if kind != "" && kind != types.EntryKindText {
+		role += " (" + strings.ReplaceAll(kind, "_", " ") + ")"
+	}
+	return role
+}
@@ -0,0 +1,76 @@
+package export
+
+import (
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+func loadThread(t *testing.T) Thread {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", "thread.json"))
+	require.NoError(t, err)
+	var thread Thread
+	require.NoError(t, json.Unmarshal(data, &thread))
+	return thread
+}
+
+func TestRender_Golden(t *testing.T) {
+	thread := loadThread(t)
+
+	for format, golden := range map[string]string{
+		FormatMarkdown: "thread.golden.md",
+		FormatJSON:     "thread.golden.json",
+		FormatText:     "thread.golden.txt",
+	} {
+		t.Run(format, func(t *testing.T) {
+			rendered, err := Render(thread, format)
+			require.NoError(t, err)
+
+			path := filepath.Join("testdata", golden)
+			if *update {
+				require.NoError(t, os.WriteFile(path, []byte(rendered), 0644))
+			}
+			expected, err := os.ReadFile(path)
+			require.NoError(t, err)
+			assert.Equal(t, string(expected), rendered)
+		})
+	}
+}
+
+func TestJSON_RoundTrip(t *testing.T) {
+	thread := loadThread(t)
+	rendered, err := JSON(thread)
+	require.NoError(t, err)
+
+	var decoded Thread
+	require.NoError(t, json.Unmarshal([]byte(rendered), &decoded))
+	assert.Equal(t, thread, decoded)
+}
+
+func TestRender_Empty(t *testing.T) {
+	assert.Equal(t, "# Conversation\n", Markdown(Thread{}))
+	assert.Equal(t, "", Text(Thread{}))
+	rendered, err := JSON(Thread{})
+	require.NoError(t, err)
+	assert.Equal(t, "{\n  \"entries\": []\n}\n", rendered)
+}
+
+func TestRender_UnknownFormat(t *testing.T) {
+	_, err := Render(Thread{}, "html")
+	assert.ErrorContains(t, err, `unknown export format "html"`)
+}
+
+func TestFormatForPath(t *testing.T) {
+	assert.Equal(t, FormatJSON, FormatForPath("chat.JSON"))
+	assert.Equal(t, FormatText, FormatForPath("/tmp/chat.txt"))
+	assert.Equal(t, FormatMarkdown, FormatForPath("chat.md"))
+	assert.Equal(t, FormatMarkdown, FormatForPath("chat"))
+}
@@ -0,0 +1,137 @@
+package gitignore
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const entryComment = "# Interactive Feedback MCP Configuration"
+
+// Ensure makes sure fileName inside projectDir is ignored by git, writing to
+// the ignore file selected by policy. Nothing is written when the policy is
+// off, when projectDir is not inside a git repository, or when an existing
+// pattern already ignores the file. It reports the file it changed, if any.
+func Ensure(projectDir, fileName string, policy Policy) (string, error) {
+	if policy == PolicyOff {
+		return "", nil
+	}
+
+	repo, err := FindRepository(projectDir)
+	if err != nil || repo == nil {
+		return "", err
+	}
+
+	target := filepath.Join(projectDir, fileName)
+	ignored, err := repo.IsIgnored(target)
+	if err != nil {
+		return "", err
+	}
+	if ignored {
+		return "", nil
+	}
+
+	switch policy {
+	case PolicyExclude:
+		rel, err := repo.relative(target)
+		if err != nil {
+			return "", err
+		}
+		excludePath := repo.ExcludePath()
+		if err := os.MkdirAll(filepath.Dir(excludePath), 0755); err != nil {
+			return "", fmt.Errorf("failed to create %s: %w", filepath.Dir(excludePath), err)
+		}
+		return excludePath, appendEntry(excludePath, "/"+rel)
+	default:
+		gitignorePath := filepath.Join(projectDir, ".gitignore")
+		return gitignorePath, appendEntry(gitignorePath, fileName)
+	}
+}
+
+// appendEntry adds a commented pattern to an ignore file, creating it if needed
+func appendEntry(path, entry string) error {
+	content, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	existing := string(content)
+	block := fmt.Sprintf("%s\n%s\n", entryComment, entry)
+	if existing != "" {
+		if !strings.HasSuffix(existing, "\n") {
+			existing += "\n"
+		}
+		block = "\n" + block
+	}
+
+	if err := os.WriteFile(path, []byte(existing+block), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+// Remove takes out the entries Ensure added for fileName inside projectDir,
+// from the project's .gitignore and from the repository's info/exclude.
+// Patterns written by hand are left alone. It reports the files it changed.
+func Remove(projectDir, fileName string) ([]string, error) {
+	repo, err := FindRepository(projectDir)
+	if err != nil || repo == nil {
+		return nil, err
+	}
+
+	rel, err := repo.relative(filepath.Join(projectDir, fileName))
+	if err != nil {
+		return nil, err
+	}
+
+	var changed []string
+	for _, file := range []struct{ path, entry string }{
+		{filepath.Join(projectDir, ".gitignore"), fileName},
+		{repo.ExcludePath(), "/" + rel},
+	} {
+		removed, err := removeEntry(file.path, file.entry)
+		if err != nil {
+			return changed, err
+		}
+		if removed {
+			changed = append(changed, file.path)
+		}
+	}
+	return changed, nil
+}
+
+// removeEntry deletes the commented patterns appendEntry wrote, along with
+// the blank line it put before them
+func removeEntry(path, entry string) (bool, error) {
+	content, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	lines := strings.Split(string(content), "\n")
+	kept := make([]string, 0, len(lines))
+	removed := false
+	for i := 0; i < len(lines); i++ {
+		if lines[i] == entryComment && i+1 < len(lines) && lines[i+1] == entry {
+			if last := len(kept) - 1; last >= 0 && kept[last] == "" {
+				kept = kept[:last]
+			}
+			i++
+			removed = true
+			continue
+		}
+		kept = append(kept, lines[i])
+	}
+	if !removed {
+		return false, nil
+	}
+
+	if err := os.WriteFile(path, []byte(strings.Join(kept, "\n")), 0644); err != nil {
+		return false, fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return true, nil
+}
@@ -0,0 +1,133 @@
+package gitignore
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const configFile = ".interactive-feedback-config.json"
+
+func TestEnsure_Gitignore(t *testing.T) {
+	root := makeRepo(t)
+
+	changed, err := Ensure(root, configFile, PolicyGitignore)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(root, ".gitignore"), changed)
+
+	content, err := os.ReadFile(filepath.Join(root, ".gitignore"))
+	require.NoError(t, err)
+	assert.Equal(t, "# Interactive Feedback MCP Configuration\n.interactive-feedback-config.json\n", string(content))
+
+	// Second call is a no-op
+	changed, err = Ensure(root, configFile, PolicyGitignore)
+	require.NoError(t, err)
+	assert.Empty(t, changed)
+}
+
+func TestEnsure_AppendsToExistingGitignore(t *testing.T) {
+	root := makeRepo(t)
+	require.NoError(t, os.WriteFile(filepath.Join(root, ".gitignore"), []byte("node_modules"), 0644))
+
+	_, err := Ensure(root, configFile, PolicyGitignore)
+	require.NoError(t, err)
+
+	content, err := os.ReadFile(filepath.Join(root, ".gitignore"))
+	require.NoError(t, err)
+	assert.Equal(t, "node_modules\n\n# Interactive Feedback MCP Configuration\n.interactive-feedback-config.json\n", string(content))
+}
+
+func TestEnsure_ExistingPatternCoversFile(t *testing.T) {
+	root := makeRepo(t)
+	require.NoError(t, os.WriteFile(filepath.Join(root, ".gitignore"), []byte(".interactive-*\n"), 0644))
+
+	changed, err := Ensure(root, configFile, PolicyGitignore)
+	require.NoError(t, err)
+	assert.Empty(t, changed)
+}
+
+func TestEnsure_Exclude(t *testing.T) {
+	root := makeRepo(t)
+	sub := filepath.Join(root, "app")
+	require.NoError(t, os.MkdirAll(sub, 0755))
+
+	changed, err := Ensure(sub, configFile, PolicyExclude)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(root, ".git", "info", "exclude"), changed)
+
+	content, err := os.ReadFile(changed)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "/app/.interactive-feedback-config.json\n")
+
+	_, err = os.Stat(filepath.Join(sub, ".gitignore"))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestEnsure_OffAndNotARepository(t *testing.T) {
+	root := makeRepo(t)
+	changed, err := Ensure(root, configFile, PolicyOff)
+	require.NoError(t, err)
+	assert.Empty(t, changed)
+
+	plain := t.TempDir()
+	changed, err = Ensure(plain, configFile, PolicyGitignore)
+	require.NoError(t, err)
+	assert.Empty(t, changed)
+	_, err = os.Stat(filepath.Join(plain, ".gitignore"))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestRemove_UndoesEnsure(t *testing.T) {
+	root := makeRepo(t)
+	gitignorePath := filepath.Join(root, ".gitignore")
+	require.NoError(t, os.WriteFile(gitignorePath, []byte("node_modules\n"), 0644))
+
+	// The exclude entry goes first, as the .gitignore one would cover app/ too
+	sub := filepath.Join(root, "app")
+	require.NoError(t, os.MkdirAll(sub, 0755))
+	_, err := Ensure(sub, configFile, PolicyExclude)
+	require.NoError(t, err)
+	_, err = Ensure(root, configFile, PolicyGitignore)
+	require.NoError(t, err)
+
+	changed, err := Remove(root, configFile)
+	require.NoError(t, err)
+	assert.Equal(t, []string{gitignorePath}, changed)
+	content, err := os.ReadFile(gitignorePath)
+	require.NoError(t, err)
+	assert.Equal(t, "node_modules\n", string(content))
+
+	changed, err = Remove(sub, configFile)
+	require.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join(root, ".git", "info", "exclude")}, changed)
+	content, err = os.ReadFile(filepath.Join(root, ".git", "info", "exclude"))
+	require.NoError(t, err)
+	assert.Empty(t, string(content))
+
+	// Nothing left to remove
+	changed, err = Remove(root, configFile)
+	require.NoError(t, err)
+	assert.Empty(t, changed)
+}
+
+func TestRemove_KeepsHandWrittenPatterns(t *testing.T) {
+	root := makeRepo(t)
+	gitignorePath := filepath.Join(root, ".gitignore")
+	require.NoError(t, os.WriteFile(gitignorePath, []byte(".interactive-feedback-config.json\n"), 0644))
+
+	changed, err := Remove(root, configFile)
+	require.NoError(t, err)
+	assert.Empty(t, changed)
+	content, err := os.ReadFile(gitignorePath)
+	require.NoError(t, err)
+	assert.Equal(t, ".interactive-feedback-config.json\n", string(content))
+}
+
+func TestRemove_NotARepository(t *testing.T) {
+	changed, err := Remove(t.TempDir(), configFile)
+	require.NoError(t, err)
+	assert.Empty(t, changed)
+}
@@ -0,0 +1,113 @@
+package gitignore
+
+import (
+	"path"
+	"strings"
+)
+
+// pattern is a single parsed line of a .gitignore or info/exclude file
+type pattern struct {
+	glob     string
+	negate   bool
+	dirOnly  bool
+	anchored bool
+}
+
+func parsePattern(line string) (pattern, bool) {
+	line = strings.TrimRight(line, " \t\r")
+	if line == "" || strings.HasPrefix(line, "#") {
+		return pattern{}, false
+	}
+
+	var p pattern
+	if strings.HasPrefix(line, "!") {
+		p.negate = true
+		line = line[1:]
+	} else if strings.HasPrefix(line, `\!`) || strings.HasPrefix(line, `\#`) {
+		line = line[1:]
+	}
+
+	if strings.HasSuffix(line, "/") {
+		p.dirOnly = true
+		line = strings.TrimSuffix(line, "/")
+	}
+
+	// A slash anywhere but the end anchors the pattern to the file's directory
+	if strings.Contains(line, "/") {
+		p.anchored = true
+		line = strings.TrimPrefix(line, "/")
+	}
+
+	if line == "" {
+		return pattern{}, false
+	}
+	p.glob = line
+	return p, true
+}
+
+// matches reports whether the pattern matches relPath (slash separated,
+// relative to the ignore file's directory) or one of its parent directories.
+func (p pattern) matches(relPath string) bool {
+	segments := strings.Split(relPath, "/")
+	for i := len(segments); i > 0; i-- {
+		isFile := i == len(segments)
+		if isFile && p.dirOnly {
+			continue
+		}
+
+		candidate := segments[:i]
+		if p.anchored {
+			if matchSegments(strings.Split(p.glob, "/"), candidate) {
+				return true
+			}
+		} else if ok, _ := path.Match(p.glob, candidate[len(candidate)-1]); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// matchSegments matches glob segments against path segments, with "**"
+// matching zero or more whole segments.
+func matchSegments(globs, segments []string) bool {
+	if len(globs) == 0 {
+		return len(segments) == 0
+	}
+
+	if globs[0] == "**" {
+		for i := 0; i <= len(segments); i++ {
+			if matchSegments(globs[1:], segments[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if len(segments) == 0 {
+		return false
+	}
+	if ok, _ := path.Match(globs[0], segments[0]); !ok {
+		return false
+	}
+	return matchSegments(globs[1:], segments[1:])
+}
+
+// Ignored evaluates ignore file lines against relPath using git's rule that
+// the last matching pattern wins.
+func Ignored(lines []string, relPath string) bool {
+	return evaluate(lines, relPath, false)
+}
+
+// evaluate applies lines on top of the result of lower-precedence ignore files
+func evaluate(lines []string, relPath string, ignored bool) bool {
+	for _, line := range lines {
+		p, ok := parsePattern(line)
+		if !ok {
+			continue
+		}
+		if p.matches(relPath) {
+			ignored = !p.negate
+		}
+	}
+	return ignored
+}
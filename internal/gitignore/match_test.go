@@ -0,0 +1,39 @@
+package gitignore
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIgnored(t *testing.T) {
+	cases := []struct {
+		name    string
+		lines   []string
+		path    string
+		ignored bool
+	}{
+		{"exact name", []string{".interactive-feedback-config.json"}, ".interactive-feedback-config.json", true},
+		{"name in subdirectory", []string{".interactive-feedback-config.json"}, "app/.interactive-feedback-config.json", true},
+		{"substring is not a match", []string{"old.interactive-feedback-config.json.bak"}, ".interactive-feedback-config.json", false},
+		{"commented out", []string{"# .interactive-feedback-config.json"}, ".interactive-feedback-config.json", false},
+		{"wildcard", []string{".interactive-*"}, ".interactive-feedback-config.json", true},
+		{"extension wildcard", []string{"*.json"}, "app/config.json", true},
+		{"anchored at root", []string{"/config.json"}, "config.json", true},
+		{"anchored does not match below", []string{"/config.json"}, "app/config.json", false},
+		{"anchored path", []string{"app/config.json"}, "app/config.json", true},
+		{"double star", []string{"**/config.json"}, "a/b/config.json", true},
+		{"double star in middle", []string{"a/**/config.json"}, "a/config.json", true},
+		{"ignored parent directory", []string{"app/"}, "app/config.json", true},
+		{"directory pattern does not match file", []string{"config.json/"}, "config.json", false},
+		{"negation", []string{"*.json", "!config.json"}, "config.json", false},
+		{"last match wins", []string{"!config.json", "*.json"}, "config.json", true},
+		{"trailing whitespace", []string{"config.json   "}, "config.json", true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.ignored, Ignored(tc.lines, tc.path))
+		})
+	}
+}
@@ -0,0 +1,192 @@
+package gitignore
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// Policy selects where the config file is excluded from version control
+type Policy string
+
+const (
+	PolicyGitignore Policy = "gitignore" // append to the project's tracked .gitignore
+	PolicyExclude   Policy = "exclude"   // append to the repository's untracked .git/info/exclude
+	PolicyOff       Policy = "off"       // never touch ignore files
+)
+
+// ParsePolicy accepts the config values for a policy, defaulting to gitignore
+func ParsePolicy(value string) (Policy, error) {
+	switch strings.TrimSpace(value) {
+	case "", string(PolicyGitignore):
+		return PolicyGitignore, nil
+	case string(PolicyExclude), ".git/info/exclude":
+		return PolicyExclude, nil
+	case string(PolicyOff):
+		return PolicyOff, nil
+	}
+	return "", fmt.Errorf("unknown gitignore policy %q (expected gitignore, exclude or off)", value)
+}
+
+// Repository locates the parts of a git checkout that hold ignore rules
+type Repository struct {
+	WorkTree  string // top-level directory of the checkout
+	GitDir    string // per-worktree git directory
+	CommonDir string // git directory shared by all worktrees; holds info/exclude
+}
+
+// FindRepository walks up from dir to the enclosing git checkout. It handles
+// regular repositories as well as linked worktrees and submodules, whose .git
+// is a file pointing at the real git directory. It returns nil if dir is not
+// inside a repository.
+func FindRepository(dir string) (*Repository, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		dotGit := filepath.Join(dir, ".git")
+		info, err := os.Stat(dotGit)
+		if err == nil {
+			if info.IsDir() {
+				return &Repository{
+					WorkTree:  dir,
+					GitDir:    dotGit,
+					CommonDir: commonDir(dotGit),
+				}, nil
+			}
+
+			gitDir, err := readGitFile(dotGit)
+			if err != nil {
+				return nil, err
+			}
+			return &Repository{
+				WorkTree:  dir,
+				GitDir:    gitDir,
+				CommonDir: commonDir(gitDir),
+			}, nil
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return nil, nil
+		}
+		dir = parent
+	}
+}
+
+// readGitFile resolves the "gitdir: <path>" indirection used by worktrees and submodules
+func readGitFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	line := strings.TrimSpace(string(data))
+	if !strings.HasPrefix(line, "gitdir:") {
+		return "", fmt.Errorf("%s is not a valid .git file", path)
+	}
+
+	gitDir := strings.TrimSpace(strings.TrimPrefix(line, "gitdir:"))
+	if !filepath.IsAbs(gitDir) {
+		gitDir = filepath.Join(filepath.Dir(path), gitDir)
+	}
+	return filepath.Clean(gitDir), nil
+}
+
+// commonDir follows the commondir file that linked worktrees use to point at the main repository
+func commonDir(gitDir string) string {
+	data, err := os.ReadFile(filepath.Join(gitDir, "commondir"))
+	if err != nil {
+		return gitDir
+	}
+
+	dir := strings.TrimSpace(string(data))
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(gitDir, dir)
+	}
+	return filepath.Clean(dir)
+}
+
+// ExcludePath returns the repository's info/exclude file
+func (r *Repository) ExcludePath() string {
+	return filepath.Join(r.CommonDir, "info", "exclude")
+}
+
+// relative returns path relative to the work tree, slash separated
+func (r *Repository) relative(path string) (string, error) {
+	rel, err := filepath.Rel(r.WorkTree, path)
+	if err != nil {
+		return "", err
+	}
+	if rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("%s is outside the work tree %s", path, r.WorkTree)
+	}
+	return filepath.ToSlash(rel), nil
+}
+
+// IsIgnored reports whether git would ignore the file at path. It asks git,
+// so every ignore source applies, including core.excludesFile. Without git
+// installed it falls back to evaluating info/exclude and every .gitignore
+// from the work tree down to the file's directory.
+func (r *Repository) IsIgnored(path string) (bool, error) {
+	rel, err := r.relative(path)
+	if err != nil {
+		return false, err
+	}
+
+	ignored, err := checkIgnore(r.WorkTree, rel)
+	if errors.Is(err, exec.ErrNotFound) {
+		return r.matchIgnored(rel), nil
+	}
+	return ignored, err
+}
+
+// checkIgnore runs git check-ignore, which exits 1 for files it does not ignore
+func checkIgnore(workTree, rel string) (bool, error) {
+	cmd := exec.Command("git", "check-ignore", "-q", "--", rel)
+	cmd.Dir = workTree
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	err := cmd.Run()
+	var exitErr *exec.ExitError
+	switch {
+	case err == nil:
+		return true, nil
+	case errors.As(err, &exitErr) && exitErr.ExitCode() == 1:
+		return false, nil
+	case errors.Is(err, exec.ErrNotFound):
+		return false, err
+	}
+	if message := strings.TrimSpace(stderr.String()); message != "" {
+		return false, fmt.Errorf("failed to run git check-ignore: %w: %s", err, message)
+	}
+	return false, fmt.Errorf("failed to run git check-ignore: %w", err)
+}
+
+// matchIgnored evaluates the ignore files git reads for rel without git
+func (r *Repository) matchIgnored(rel string) bool {
+	ignored := evaluate(readLines(r.ExcludePath()), rel, false)
+
+	dir := r.WorkTree
+	parts := strings.Split(rel, "/")
+	for i := 0; i < len(parts); i++ {
+		ignored = evaluate(readLines(filepath.Join(dir, ".gitignore")), strings.Join(parts[i:], "/"), ignored)
+		dir = filepath.Join(dir, parts[i])
+	}
+	return ignored
+}
+
+func readLines(path string) []string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	return strings.Split(string(data), "\n")
+}
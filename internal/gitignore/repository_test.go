@@ -0,0 +1,171 @@
+package gitignore
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// makeRepo creates a repository, with git when it is installed, and keeps the
+// user's git config out of the tests
+func makeRepo(t *testing.T) string {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+
+	dir, err := filepath.EvalSymlinks(t.TempDir())
+	require.NoError(t, err)
+	if _, err := exec.LookPath("git"); err == nil {
+		runGit(t, dir, "init", "-q", "--template=")
+	}
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, ".git", "info"), 0755))
+	return dir
+}
+
+func runGit(t *testing.T, dir string, args ...string) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	output, err := cmd.CombinedOutput()
+	require.NoError(t, err, string(output))
+}
+
+func TestParsePolicy(t *testing.T) {
+	policy, err := ParsePolicy("")
+	require.NoError(t, err)
+	assert.Equal(t, PolicyGitignore, policy)
+
+	policy, err = ParsePolicy(".git/info/exclude")
+	require.NoError(t, err)
+	assert.Equal(t, PolicyExclude, policy)
+
+	policy, err = ParsePolicy("off")
+	require.NoError(t, err)
+	assert.Equal(t, PolicyOff, policy)
+
+	_, err = ParsePolicy("sometimes")
+	assert.Error(t, err)
+}
+
+func TestFindRepository(t *testing.T) {
+	root := makeRepo(t)
+	sub := filepath.Join(root, "pkg", "app")
+	require.NoError(t, os.MkdirAll(sub, 0755))
+
+	repo, err := FindRepository(sub)
+	require.NoError(t, err)
+	require.NotNil(t, repo)
+	assert.Equal(t, root, repo.WorkTree)
+	assert.Equal(t, filepath.Join(root, ".git"), repo.CommonDir)
+	assert.Equal(t, filepath.Join(root, ".git", "info", "exclude"), repo.ExcludePath())
+}
+
+func TestFindRepository_NotARepository(t *testing.T) {
+	dir := t.TempDir()
+
+	repo, err := FindRepository(dir)
+	require.NoError(t, err)
+	assert.Nil(t, repo)
+}
+
+func TestFindRepository_Worktree(t *testing.T) {
+	main := makeRepo(t)
+	worktreeGitDir := filepath.Join(main, ".git", "worktrees", "feature")
+	require.NoError(t, os.MkdirAll(worktreeGitDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(worktreeGitDir, "commondir"), []byte("../..\n"), 0644))
+
+	worktree, err := filepath.EvalSymlinks(t.TempDir())
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(worktree, ".git"), []byte("gitdir: "+worktreeGitDir+"\n"), 0644))
+
+	repo, err := FindRepository(worktree)
+	require.NoError(t, err)
+	require.NotNil(t, repo)
+	assert.Equal(t, worktree, repo.WorkTree)
+	assert.Equal(t, worktreeGitDir, repo.GitDir)
+	assert.Equal(t, filepath.Join(main, ".git"), repo.CommonDir)
+}
+
+func TestFindRepository_Submodule(t *testing.T) {
+	parent := makeRepo(t)
+	moduleGitDir := filepath.Join(parent, ".git", "modules", "lib")
+	require.NoError(t, os.MkdirAll(moduleGitDir, 0755))
+
+	submodule := filepath.Join(parent, "lib")
+	require.NoError(t, os.MkdirAll(submodule, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(submodule, ".git"), []byte("gitdir: ../.git/modules/lib\n"), 0644))
+
+	repo, err := FindRepository(submodule)
+	require.NoError(t, err)
+	require.NotNil(t, repo)
+	assert.Equal(t, submodule, repo.WorkTree)
+	assert.Equal(t, moduleGitDir, repo.CommonDir)
+}
+
+func TestRepository_IsIgnored(t *testing.T) {
+	root := makeRepo(t)
+	sub := filepath.Join(root, "app")
+	require.NoError(t, os.MkdirAll(sub, 0755))
+	target := filepath.Join(sub, "config.json")
+
+	repo, err := FindRepository(sub)
+	require.NoError(t, err)
+
+	ignored, err := repo.IsIgnored(target)
+	require.NoError(t, err)
+	assert.False(t, ignored)
+
+	// Root .gitignore applies to files in subdirectories
+	require.NoError(t, os.WriteFile(filepath.Join(root, ".gitignore"), []byte("*.json\n"), 0644))
+	ignored, err = repo.IsIgnored(target)
+	require.NoError(t, err)
+	assert.True(t, ignored)
+
+	// A deeper .gitignore overrides it
+	require.NoError(t, os.WriteFile(filepath.Join(sub, ".gitignore"), []byte("!config.json\n"), 0644))
+	ignored, err = repo.IsIgnored(target)
+	require.NoError(t, err)
+	assert.False(t, ignored)
+}
+
+func TestRepository_IsIgnored_FollowsGit(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	root := makeRepo(t)
+	repo, err := FindRepository(root)
+	require.NoError(t, err)
+
+	// The user's global excludes file applies
+	excludesFile := filepath.Join(t.TempDir(), "ignore")
+	require.NoError(t, os.WriteFile(excludesFile, []byte("*.local.json\n"), 0644))
+	runGit(t, root, "config", "core.excludesFile", excludesFile)
+	ignored, err := repo.IsIgnored(filepath.Join(root, "settings.local.json"))
+	require.NoError(t, err)
+	assert.True(t, ignored)
+
+	// Files cannot be re-included once their directory is excluded
+	require.NoError(t, os.WriteFile(filepath.Join(root, ".gitignore"), []byte("build/\n!build/config.json\n"), 0644))
+	ignored, err = repo.IsIgnored(filepath.Join(root, "build", "config.json"))
+	require.NoError(t, err)
+	assert.True(t, ignored)
+}
+
+func TestRepository_IsIgnored_WithoutGit(t *testing.T) {
+	root := makeRepo(t)
+	t.Setenv("PATH", t.TempDir())
+	repo, err := FindRepository(root)
+	require.NoError(t, err)
+
+	require.NoError(t, os.WriteFile(filepath.Join(root, ".gitignore"), []byte("*.json\n"), 0644))
+	ignored, err := repo.IsIgnored(filepath.Join(root, "config.json"))
+	require.NoError(t, err)
+	assert.True(t, ignored)
+
+	ignored, err = repo.IsIgnored(filepath.Join(root, "main.go"))
+	require.NoError(t, err)
+	assert.False(t, ignored)
+}
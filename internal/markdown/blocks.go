@@ -0,0 +1,80 @@
+package markdown
+
+import "strings"
+
+// Block is either a run of Markdown or a fenced code block
+type Block struct {
+	Code     bool
+	Language string // first word of the fence's info string
+	Text     string // without the fences for code blocks
+}
+
+// Split separates fenced code blocks from the Markdown around them. An
+// unclosed fence runs to the end of the text, as in CommonMark.
+func Split(text string) []Block {
+	var blocks []Block
+	var current []string
+	var fence string
+	language := ""
+
+	flush := func(code bool) {
+		joined := strings.Join(current, "\n")
+		if code || strings.TrimSpace(joined) != "" {
+			blocks = append(blocks, Block{Code: code, Language: language, Text: joined})
+		}
+		current = nil
+		language = ""
+	}
+
+	for _, line := range strings.Split(text, "\n") {
+		if fence == "" {
+			if marker, info, ok := openingFence(line); ok {
+				flush(false)
+				fence = marker
+				language = info
+				continue
+			}
+		} else if isClosingFence(line, fence) {
+			flush(true)
+			fence = ""
+			continue
+		}
+		current = append(current, line)
+	}
+	flush(fence != "")
+	return blocks
+}
+
+// openingFence reports whether line opens a code block, returning the fence
+// and the language
+func openingFence(line string) (string, string, bool) {
+	trimmed := strings.TrimLeft(line, " ")
+	if len(line)-len(trimmed) > 3 || len(trimmed) < 3 {
+		return "", "", false
+	}
+	char := trimmed[0]
+	if char != '`' && char != '~' {
+		return "", "", false
+	}
+	length := 0
+	for length < len(trimmed) && trimmed[length] == char {
+		length++
+	}
+	if length < 3 {
+		return "", "", false
+	}
+	info := strings.TrimSpace(trimmed[length:])
+	if char == '`' && strings.Contains(info, "`") {
+		return "", "", false
+	}
+	language, _, _ := strings.Cut(info, " ")
+	return trimmed[:length], strings.ToLower(language), true
+}
+
+func isClosingFence(line, fence string) bool {
+	trimmed := strings.TrimSpace(line)
+	if len(line)-len(strings.TrimLeft(line, " ")) > 3 || len(trimmed) < len(fence) {
+		return false
+	}
+	return strings.Trim(trimmed, fence[:1]) == ""
+}
@@ -0,0 +1,57 @@
+package markdown
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplit(t *testing.T) {
+	tests := map[string]struct {
+		text     string
+		expected []Block
+	}{
+		"plain markdown": {
+			"# Title\n\n- a\n- b",
+			[]Block{{Text: "# Title\n\n- a\n- b"}},
+		},
+		"code between text": {
+			"Run this:\n```Go\nfmt.Println(1)\n```\nThen check.",
+			[]Block{
+				{Text: "Run this:"},
+				{Code: true, Language: "go", Text: "fmt.Println(1)"},
+				{Text: "Then check."},
+			},
+		},
+		"tilde fence with info string": {
+			"~~~python title=x\nprint(1)\n~~~",
+			[]Block{{Code: true, Language: "python", Text: "print(1)"}},
+		},
+		"longer fence holds a shorter one": {
+			"````md\n```go\nx\n```\n````",
+			[]Block{{Code: true, Language: "md", Text: "```go\nx\n```"}},
+		},
+		"unclosed fence runs to the end": {
+			"```\nline 1\nline 2",
+			[]Block{{Code: true, Text: "line 1\nline 2"}},
+		},
+		"empty code block": {
+			"```\n```",
+			[]Block{{Code: true}},
+		},
+		"inline backticks are not a fence": {
+			"``` `x` ```",
+			[]Block{{Text: "``` `x` ```"}},
+		},
+		"indented four spaces is not a fence": {
+			"    ```\n    x",
+			[]Block{{Text: "    ```\n    x"}},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.expected, Split(test.text))
+		})
+	}
+}
@@ -0,0 +1,243 @@
+package markdown
+
+import (
+	"strings"
+	"unicode"
+)
+
+// TokenKind classifies a piece of highlighted code
+type TokenKind int
+
+const (
+	TokenPlain TokenKind = iota
+	TokenKeyword
+	TokenString
+	TokenComment
+	TokenNumber
+)
+
+// Token is a run of code of one kind
+type Token struct {
+	Text string
+	Kind TokenKind
+}
+
+// syntax is just enough of a language to colour it
+type syntax struct {
+	keywords       []string
+	caseFold       bool // keywords match in any case
+	lineComments   []string
+	blockComment   [2]string
+	quotes         string
+	multilineQuote byte // quote whose strings may span lines
+}
+
+var (
+	cLike = syntax{
+		keywords:     []string{"break", "case", "char", "class", "const", "continue", "default", "do", "double", "else", "enum", "extends", "false", "final", "float", "for", "if", "implements", "import", "int", "interface", "long", "new", "null", "nullptr", "package", "private", "protected", "public", "return", "static", "struct", "switch", "this", "throw", "true", "try", "catch", "typedef", "unsigned", "void", "while"},
+		lineComments: []string{"//"},
+		blockComment: [2]string{"/*", "*/"},
+		quotes:       `"'`,
+	}
+	goSyntax = syntax{
+		keywords:       []string{"break", "case", "chan", "const", "continue", "default", "defer", "else", "fallthrough", "false", "for", "func", "go", "goto", "if", "import", "interface", "iota", "map", "nil", "package", "range", "return", "select", "struct", "switch", "true", "type", "var"},
+		lineComments:   []string{"//"},
+		blockComment:   [2]string{"/*", "*/"},
+		quotes:         "\"'`",
+		multilineQuote: '`',
+	}
+	javaScript = syntax{
+		keywords:       []string{"async", "await", "break", "case", "catch", "class", "const", "continue", "default", "delete", "do", "else", "export", "extends", "false", "finally", "for", "from", "function", "if", "import", "in", "instanceof", "interface", "let", "new", "null", "of", "return", "switch", "this", "throw", "true", "try", "type", "typeof", "undefined", "var", "void", "while", "yield"},
+		lineComments:   []string{"//"},
+		blockComment:   [2]string{"/*", "*/"},
+		quotes:         "\"'`",
+		multilineQuote: '`',
+	}
+	python = syntax{
+		keywords:     []string{"False", "None", "True", "and", "as", "assert", "async", "await", "break", "class", "continue", "def", "del", "elif", "else", "except", "finally", "for", "from", "global", "if", "import", "in", "is", "lambda", "nonlocal", "not", "or", "pass", "raise", "return", "try", "while", "with", "yield"},
+		lineComments: []string{"#"},
+		quotes:       `"'`,
+	}
+	rust = syntax{
+		keywords:     []string{"as", "async", "await", "break", "const", "continue", "crate", "else", "enum", "false", "fn", "for", "if", "impl", "in", "let", "loop", "match", "mod", "move", "mut", "pub", "ref", "return", "self", "Self", "static", "struct", "trait", "true", "type", "unsafe", "use", "where", "while"},
+		lineComments: []string{"//"},
+		blockComment: [2]string{"/*", "*/"},
+		quotes:       `"`,
+	}
+	shell = syntax{
+		keywords:     []string{"case", "do", "done", "elif", "else", "esac", "export", "fi", "for", "function", "if", "in", "local", "return", "then", "until", "while"},
+		lineComments: []string{"#"},
+		quotes:       `"'`,
+	}
+	sql = syntax{
+		keywords:     []string{"and", "as", "by", "create", "delete", "desc", "distinct", "drop", "from", "group", "having", "in", "insert", "into", "is", "join", "left", "limit", "not", "null", "on", "or", "order", "right", "select", "set", "table", "update", "values", "where"},
+		caseFold:     true,
+		lineComments: []string{"--"},
+		blockComment: [2]string{"/*", "*/"},
+		quotes:       `'"`,
+	}
+	jsonSyntax = syntax{
+		keywords: []string{"false", "null", "true"},
+		quotes:   `"`,
+	}
+	yaml = syntax{
+		keywords:     []string{"false", "null", "true", "yes", "no"},
+		lineComments: []string{"#"},
+		quotes:       `"'`,
+	}
+)
+
+// languages maps fence languages to their syntax
+var languages = map[string]*syntax{
+	"go":         &goSyntax,
+	"golang":     &goSyntax,
+	"python":     &python,
+	"py":         &python,
+	"javascript": &javaScript,
+	"js":         &javaScript,
+	"jsx":        &javaScript,
+	"typescript": &javaScript,
+	"ts":         &javaScript,
+	"tsx":        &javaScript,
+	"rust":       &rust,
+	"rs":         &rust,
+	"sh":         &shell,
+	"bash":       &shell,
+	"shell":      &shell,
+	"zsh":        &shell,
+	"sql":        &sql,
+	"json":       &jsonSyntax,
+	"yaml":       &yaml,
+	"yml":        &yaml,
+	"c":          &cLike,
+	"cpp":        &cLike,
+	"c++":        &cLike,
+	"java":       &cLike,
+	"csharp":     &cLike,
+	"cs":         &cLike,
+}
+
+// Highlight splits code into tokens for colouring. Code in a language that
+// is not known comes back as a single plain token.
+func Highlight(code, language string) []Token {
+	lang, ok := languages[strings.ToLower(language)]
+	if !ok {
+		if code == "" {
+			return nil
+		}
+		return []Token{{Text: code, Kind: TokenPlain}}
+	}
+
+	var tokens []Token
+	emit := func(text string, kind TokenKind) {
+		if text == "" {
+			return
+		}
+		if last := len(tokens) - 1; last >= 0 && tokens[last].Kind == kind {
+			tokens[last].Text += text
+			return
+		}
+		tokens = append(tokens, Token{Text: text, Kind: kind})
+	}
+
+	for i := 0; i < len(code); {
+		rest := code[i:]
+		if lang.startsLineComment(rest) {
+			end := strings.IndexByte(rest, '\n')
+			if end < 0 {
+				end = len(rest)
+			}
+			emit(rest[:end], TokenComment)
+			i += end
+			continue
+		}
+		if open := lang.blockComment[0]; open != "" && strings.HasPrefix(rest, open) {
+			end := strings.Index(rest[len(open):], lang.blockComment[1])
+			if end < 0 {
+				end = len(rest)
+			} else {
+				end += len(open) + len(lang.blockComment[1])
+			}
+			emit(rest[:end], TokenComment)
+			i += end
+			continue
+		}
+		if quote := rest[0]; strings.IndexByte(lang.quotes, quote) >= 0 {
+			end := stringEnd(rest, quote, quote == lang.multilineQuote)
+			emit(rest[:end], TokenString)
+			i += end
+			continue
+		}
+
+		switch {
+		case rest[0] >= '0' && rest[0] <= '9':
+			end := wordEnd(rest, true)
+			emit(rest[:end], TokenNumber)
+			i += end
+		case isWordByte(rest[0]):
+			end := wordEnd(rest, false)
+			if lang.isKeyword(rest[:end]) {
+				emit(rest[:end], TokenKeyword)
+			} else {
+				emit(rest[:end], TokenPlain)
+			}
+			i += end
+		default:
+			emit(rest[:1], TokenPlain)
+			i++
+		}
+	}
+	return tokens
+}
+
+func (s *syntax) startsLineComment(text string) bool {
+	for _, prefix := range s.lineComments {
+		if strings.HasPrefix(text, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *syntax) isKeyword(word string) bool {
+	for _, keyword := range s.keywords {
+		if word == keyword || (s.caseFold && strings.EqualFold(word, keyword)) {
+			return true
+		}
+	}
+	return false
+}
+
+// stringEnd returns the length of the string literal at the start of text,
+// which runs to the closing quote, or the end of the line if unclosed
+func stringEnd(text string, quote byte, multiline bool) int {
+	for i := 1; i < len(text); i++ {
+		switch text[i] {
+		case '\\':
+			if !multiline {
+				i++
+			}
+		case quote:
+			return i + 1
+		case '\n':
+			if !multiline {
+				return i
+			}
+		}
+	}
+	return len(text)
+}
+
+// wordEnd returns the length of the identifier or number at the start of text
+func wordEnd(text string, number bool) int {
+	for i := 0; i < len(text); i++ {
+		if !isWordByte(text[i]) && !(number && text[i] == '.') {
+			return i
+		}
+	}
+	return len(text)
+}
+
+func isWordByte(b byte) bool {
+	return b == '_' || b >= 0x80 || unicode.IsLetter(rune(b)) || unicode.IsDigit(rune(b))
+}
@@ -0,0 +1,75 @@
+package markdown
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHighlight(t *testing.T) {
+	tests := map[string]struct {
+		code     string
+		language string
+		expected []Token
+	}{
+		"go": {
+			"func f() int { return 42 } // answer",
+			"go",
+			[]Token{
+				{"func", TokenKeyword}, {" f() int { ", TokenPlain}, {"return", TokenKeyword},
+				{" ", TokenPlain}, {"42", TokenNumber}, {" } ", TokenPlain}, {"// answer", TokenComment},
+			},
+		},
+		"go raw string spans lines": {
+			"x := `a\nb`",
+			"golang",
+			[]Token{{"x := ", TokenPlain}, {"`a\nb`", TokenString}},
+		},
+		"escaped quote": {
+			`s = "say \"hi\"" # greet`,
+			"python",
+			[]Token{{"s = ", TokenPlain}, {`"say \"hi\""`, TokenString}, {" ", TokenPlain}, {"# greet", TokenComment}},
+		},
+		"unclosed string ends at the line": {
+			"echo \"oops\nfi",
+			"bash",
+			[]Token{{"echo ", TokenPlain}, {"\"oops", TokenString}, {"\n", TokenPlain}, {"fi", TokenKeyword}},
+		},
+		"sql keywords in any case": {
+			"Select id FROM t -- all",
+			"SQL",
+			[]Token{{"Select", TokenKeyword}, {" id ", TokenPlain}, {"FROM", TokenKeyword}, {" t ", TokenPlain}, {"-- all", TokenComment}},
+		},
+		"block comment": {
+			"/* a\nb */ x",
+			"js",
+			[]Token{{"/* a\nb */", TokenComment}, {" x", TokenPlain}},
+		},
+		"identifiers holding digits are not numbers": {
+			"v2 = 3.14",
+			"py",
+			[]Token{{"v2 = ", TokenPlain}, {"3.14", TokenNumber}},
+		},
+		"json": {
+			`{"a": true}`,
+			"json",
+			[]Token{{"{", TokenPlain}, {`"a"`, TokenString}, {": ", TokenPlain}, {"true", TokenKeyword}, {"}", TokenPlain}},
+		},
+		"unknown language": {
+			"if x { }",
+			"brainfuck",
+			[]Token{{"if x { }", TokenPlain}},
+		},
+		"no language": {
+			"",
+			"",
+			nil,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.expected, Highlight(test.code, test.language))
+		})
+	}
+}
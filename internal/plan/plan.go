@@ -0,0 +1,125 @@
+package plan
+
+import (
+	"fmt"
+	"strings"
+
+	"interactive-feedback-mcp/internal/types"
+)
+
+// EditedStep is a step as returned by the plan editor
+type EditedStep struct {
+	Text     string `json:"text"`
+	Original *int   `json:"original"` // index of the proposed step; null for added steps
+	Approved bool   `json:"approved"`
+}
+
+// Decisions is what the plan editor returns: the steps in their new order
+// and whether the user approved or rejected the plan
+type Decisions struct {
+	Steps   []EditedStep `json:"steps"`
+	Verdict string       `json:"verdict"` // approved or rejected
+	Comment string       `json:"comment"`
+}
+
+// Reconcile compares the edited steps with the proposed ones. Steps left
+// empty are dropped; the plan is partially approved when the user approved
+// it but unchecked some of its steps.
+func Reconcile(title string, proposed []string, decisions Decisions) (types.Plan, error) {
+	if decisions.Verdict != types.VerdictApproved && decisions.Verdict != types.VerdictRejected {
+		return types.Plan{}, fmt.Errorf("invalid plan verdict %q", decisions.Verdict)
+	}
+
+	result := types.Plan{
+		Title:    title,
+		Proposed: proposed,
+		Steps:    []types.PlanStep{},
+		Comment:  decisions.Comment,
+	}
+
+	kept := make([]bool, len(proposed))
+	approved := 0
+	for _, edited := range decisions.Steps {
+		text := strings.TrimSpace(edited.Text)
+		if text == "" {
+			continue
+		}
+
+		step := types.PlanStep{Text: text, Approved: edited.Approved}
+		if edited.Original != nil {
+			index := *edited.Original
+			if index < 0 || index >= len(proposed) {
+				return types.Plan{}, fmt.Errorf("step %q refers to unknown proposed step %d", text, index)
+			}
+			if kept[index] {
+				return types.Plan{}, fmt.Errorf("proposed step %d appears more than once", index)
+			}
+			kept[index] = true
+			step.Original = &index
+			step.Edited = text != strings.TrimSpace(proposed[index])
+		}
+		if step.Approved {
+			approved++
+		}
+		result.Steps = append(result.Steps, step)
+	}
+
+	for index, wasKept := range kept {
+		if !wasKept {
+			result.Removed = append(result.Removed, index)
+		}
+	}
+
+	switch {
+	case decisions.Verdict == types.VerdictRejected || approved == 0:
+		result.Verdict = types.VerdictRejected
+	case approved < len(result.Steps):
+		result.Verdict = types.VerdictPartial
+	default:
+		result.Verdict = types.VerdictApproved
+	}
+	return result, nil
+}
+
+// Cancelled is the result when the editor was closed without a decision
+func Cancelled(title string, proposed []string) types.Plan {
+	steps := make([]types.PlanStep, len(proposed))
+	for i, text := range proposed {
+		index := i
+		steps[i] = types.PlanStep{Text: text, Original: &index}
+	}
+	return types.Plan{Title: title, Proposed: proposed, Steps: steps, Verdict: types.VerdictCancelled}
+}
+
+// Summary renders a plan as text for the conversation history
+func Summary(plan types.Plan) string {
+	var text strings.Builder
+	if plan.Title != "" {
+		fmt.Fprintf(&text, "Plan: %s (%s)\n", plan.Title, plan.Verdict)
+	} else {
+		fmt.Fprintf(&text, "Plan (%s)\n", plan.Verdict)
+	}
+
+	for i, step := range plan.Steps {
+		mark := " "
+		if step.Approved {
+			mark = "x"
+		}
+		var note string
+		switch {
+		case step.Original == nil:
+			note = " (added)"
+		case step.Edited:
+			note = " (edited)"
+		}
+		fmt.Fprintf(&text, "%d. [%s] %s%s\n", i+1, mark, step.Text, note)
+	}
+
+	for _, index := range plan.Removed {
+		fmt.Fprintf(&text, "Removed: %s\n", plan.Proposed[index])
+	}
+	if plan.Comment != "" {
+		fmt.Fprintf(&text, "Comment: %s\n", plan.Comment)
+	}
+	return strings.TrimSuffix(text.String(), "\n")
+}
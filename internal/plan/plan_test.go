@@ -0,0 +1,102 @@
+package plan
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"interactive-feedback-mcp/internal/types"
+)
+
+func index(i int) *int {
+	return &i
+}
+
+var proposed = []string{"Add the migration", "Update the model", "Write tests"}
+
+func TestReconcile(t *testing.T) {
+	result, err := Reconcile("Add user roles", proposed, Decisions{
+		Verdict: types.VerdictApproved,
+		Comment: "Tests first",
+		Steps: []EditedStep{
+			{Text: "Write tests", Original: index(2), Approved: true},
+			{Text: "Add the migration with a rollback", Original: index(0), Approved: true},
+			{Text: "Document the roles", Approved: true},
+			{Text: "  ", Approved: true},
+		},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, types.VerdictApproved, result.Verdict)
+	assert.Equal(t, "Add user roles", result.Title)
+	assert.Equal(t, proposed, result.Proposed)
+	assert.Equal(t, []types.PlanStep{
+		{Text: "Write tests", Original: index(2), Approved: true},
+		{Text: "Add the migration with a rollback", Original: index(0), Edited: true, Approved: true},
+		{Text: "Document the roles", Approved: true},
+	}, result.Steps)
+	assert.Equal(t, []int{1}, result.Removed)
+	assert.Equal(t, "Tests first", result.Comment)
+}
+
+func TestReconcile_Verdicts(t *testing.T) {
+	steps := []EditedStep{
+		{Text: proposed[0], Original: index(0), Approved: true},
+		{Text: proposed[1], Original: index(1), Approved: false},
+	}
+
+	result, err := Reconcile("", proposed, Decisions{Verdict: types.VerdictApproved, Steps: steps})
+	require.NoError(t, err)
+	assert.Equal(t, types.VerdictPartial, result.Verdict)
+
+	result, err = Reconcile("", proposed, Decisions{Verdict: types.VerdictRejected, Steps: steps})
+	require.NoError(t, err)
+	assert.Equal(t, types.VerdictRejected, result.Verdict)
+
+	// Approving a plan with every step unchecked approves nothing
+	result, err = Reconcile("", proposed, Decisions{Verdict: types.VerdictApproved, Steps: steps[1:]})
+	require.NoError(t, err)
+	assert.Equal(t, types.VerdictRejected, result.Verdict)
+}
+
+func TestReconcile_Errors(t *testing.T) {
+	_, err := Reconcile("", proposed, Decisions{Verdict: "maybe"})
+	assert.Error(t, err)
+
+	_, err = Reconcile("", proposed, Decisions{Verdict: types.VerdictApproved, Steps: []EditedStep{{Text: "x", Original: index(7)}}})
+	assert.Error(t, err)
+
+	_, err = Reconcile("", proposed, Decisions{Verdict: types.VerdictApproved, Steps: []EditedStep{
+		{Text: "a", Original: index(0)},
+		{Text: "b", Original: index(0)},
+	}})
+	assert.Error(t, err)
+}
+
+func TestCancelled(t *testing.T) {
+	result := Cancelled("Roles", proposed)
+	assert.Equal(t, types.VerdictCancelled, result.Verdict)
+	require.Len(t, result.Steps, 3)
+	assert.Equal(t, index(1), result.Steps[1].Original)
+	assert.False(t, result.Steps[1].Approved)
+}
+
+func TestSummary(t *testing.T) {
+	result, err := Reconcile("Add user roles", proposed, Decisions{
+		Verdict: types.VerdictApproved,
+		Comment: "Looks good",
+		Steps: []EditedStep{
+			{Text: "Add the migration", Original: index(0), Approved: true},
+			{Text: "Update the model and views", Original: index(1), Approved: false},
+			{Text: "Deploy", Approved: true},
+		},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "Plan: Add user roles (partial)\n"+
+		"1. [x] Add the migration\n"+
+		"2. [ ] Update the model and views (edited)\n"+
+		"3. [x] Deploy (added)\n"+
+		"Removed: Write tests\n"+
+		"Comment: Looks good", Summary(result))
+}
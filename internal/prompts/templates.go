@@ -0,0 +1,188 @@
+package prompts
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ProjectFileName is the shared, committable prompt file inside a project
+const ProjectFileName = ".interactive-feedback-prompts.json"
+
+// UserFileName is the prompt file inside the user config directory
+const UserFileName = "prompts.json"
+
+// Argument describes a value the client must supply when getting a prompt
+type Argument struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Required    bool   `json:"required,omitempty"`
+}
+
+// Template is a reusable agent prompt that ends with an interactive_feedback call
+type Template struct {
+	Name        string                 `json:"name"`
+	Description string                 `json:"description,omitempty"`
+	Arguments   []Argument             `json:"arguments,omitempty"`
+	Text        string                 `json:"text"`
+	Feedback    map[string]interface{} `json:"feedback,omitempty"` // interactive_feedback arguments
+}
+
+// File is the on-disk format of a prompt file
+type File struct {
+	Prompts []Template `json:"prompts"`
+}
+
+// Builtin returns the templates shipped with the server
+func Builtin() []Template {
+	return []Template{
+		{
+			Name:        "confirm_plan",
+			Description: "Present a plan and wait for confirmation before executing it",
+			Arguments: []Argument{
+				{Name: "task", Description: "What the user asked for", Required: true},
+			},
+			Text: "Before changing anything for the task below, write a short numbered plan of the steps you intend to take.\n\nTask: {{task}}",
+			Feedback: map[string]interface{}{
+				"prompt":              "Here is my plan. Should I go ahead, or would you like changes?",
+				"previousUserRequest": "{{task}}",
+			},
+		},
+		{
+			Name:        "review_diff",
+			Description: "Summarize the current changes and ask the user to review them",
+			Arguments: []Argument{
+				{Name: "focus", Description: "Area the reviewer should pay attention to"},
+			},
+			Text: "Summarize the changes you have made so far, file by file, and point out anything risky. {{focus}}",
+			Feedback: map[string]interface{}{
+				"prompt": "Please review the changes above. Anything to fix before I continue?",
+			},
+		},
+		{
+			Name:        "choose_approach",
+			Description: "Lay out alternative approaches and let the user pick one",
+			Arguments: []Argument{
+				{Name: "problem", Description: "The decision to be made", Required: true},
+			},
+			Text: "Describe two or three viable approaches to the problem below, with their trade-offs, and recommend one.\n\nProblem: {{problem}}",
+			Feedback: map[string]interface{}{
+				"prompt":              "Which approach should I take?",
+				"previousUserRequest": "{{problem}}",
+			},
+		},
+	}
+}
+
+// Load returns the builtin templates overlaid with the user and project prompt files.
+// Later sources replace earlier templates with the same name.
+func Load(userConfigDir, projectDir string) ([]Template, error) {
+	byName := make(map[string]Template)
+	for _, tmpl := range Builtin() {
+		byName[tmpl.Name] = tmpl
+	}
+
+	paths := []string{}
+	if userConfigDir != "" {
+		paths = append(paths, filepath.Join(userConfigDir, UserFileName))
+	}
+	if projectDir != "" {
+		paths = append(paths, filepath.Join(projectDir, ProjectFileName))
+	}
+
+	for _, path := range paths {
+		templates, err := loadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		for _, tmpl := range templates {
+			byName[tmpl.Name] = tmpl
+		}
+	}
+
+	templates := make([]Template, 0, len(byName))
+	for _, tmpl := range byName {
+		templates = append(templates, tmpl)
+	}
+	sort.Slice(templates, func(i, j int) bool {
+		return templates[i].Name < templates[j].Name
+	})
+
+	return templates, nil
+}
+
+func loadFile(path string) ([]Template, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read prompt file %s: %w", path, err)
+	}
+
+	var file File
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse prompt file %s: %w", path, err)
+	}
+
+	for i, tmpl := range file.Prompts {
+		if tmpl.Name == "" {
+			return nil, fmt.Errorf("prompt %d in %s has no name", i, path)
+		}
+	}
+
+	return file.Prompts, nil
+}
+
+// Find returns the template with the given name
+func Find(templates []Template, name string) (Template, bool) {
+	for _, tmpl := range templates {
+		if tmpl.Name == name {
+			return tmpl, true
+		}
+	}
+	return Template{}, false
+}
+
+// Render substitutes {{argument}} placeholders and appends the instruction to
+// call interactive_feedback with the template's options.
+func Render(tmpl Template, args map[string]string) (string, error) {
+	for _, arg := range tmpl.Arguments {
+		if arg.Required && strings.TrimSpace(args[arg.Name]) == "" {
+			return "", fmt.Errorf("missing required argument %q", arg.Name)
+		}
+	}
+
+	pairs := make([]string, 0, len(tmpl.Arguments)*2)
+	for _, arg := range tmpl.Arguments {
+		pairs = append(pairs, "{{"+arg.Name+"}}", args[arg.Name])
+	}
+	replacer := strings.NewReplacer(pairs...)
+
+	var text strings.Builder
+	text.WriteString(strings.TrimSpace(replacer.Replace(tmpl.Text)))
+
+	if len(tmpl.Feedback) > 0 {
+		feedback := make(map[string]interface{}, len(tmpl.Feedback))
+		for key, value := range tmpl.Feedback {
+			if str, ok := value.(string); ok {
+				value = replacer.Replace(str)
+			}
+			feedback[key] = value
+		}
+
+		data, err := json.MarshalIndent(feedback, "", "  ")
+		if err != nil {
+			return "", fmt.Errorf("failed to encode feedback options: %w", err)
+		}
+
+		text.WriteString("\n\nThen call the `interactive_feedback` tool with the project directory and these arguments, and wait for the answer before continuing:\n\n```json\n")
+		text.Write(data)
+		text.WriteString("\n```")
+	}
+
+	return text.String(), nil
+}
@@ -0,0 +1,71 @@
+package prompts
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad_BuiltinOnly(t *testing.T) {
+	templates, err := Load("", "")
+	require.NoError(t, err)
+
+	_, ok := Find(templates, "confirm_plan")
+	assert.True(t, ok)
+	_, ok = Find(templates, "review_diff")
+	assert.True(t, ok)
+	_, ok = Find(templates, "choose_approach")
+	assert.True(t, ok)
+}
+
+func TestLoad_ProjectOverridesUser(t *testing.T) {
+	userDir := t.TempDir()
+	projectDir := t.TempDir()
+
+	userFile := `{"prompts": [{"name": "checklist", "text": "user version"}, {"name": "mine", "text": "only in user"}]}`
+	projectFile := `{"prompts": [{"name": "checklist", "text": "project version"}]}`
+	require.NoError(t, os.WriteFile(filepath.Join(userDir, UserFileName), []byte(userFile), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(projectDir, ProjectFileName), []byte(projectFile), 0644))
+
+	templates, err := Load(userDir, projectDir)
+	require.NoError(t, err)
+
+	checklist, ok := Find(templates, "checklist")
+	require.True(t, ok)
+	assert.Equal(t, "project version", checklist.Text)
+
+	_, ok = Find(templates, "mine")
+	assert.True(t, ok)
+}
+
+func TestLoad_InvalidFile(t *testing.T) {
+	projectDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(projectDir, ProjectFileName), []byte("invalid json"), 0644))
+
+	_, err := Load("", projectDir)
+	assert.Error(t, err)
+}
+
+func TestRender(t *testing.T) {
+	tmpl := Template{
+		Name:      "confirm",
+		Arguments: []Argument{{Name: "task", Required: true}},
+		Text:      "Plan for {{task}}",
+		Feedback: map[string]interface{}{
+			"prompt":              "OK to proceed?",
+			"previousUserRequest": "{{task}}",
+		},
+	}
+
+	text, err := Render(tmpl, map[string]string{"task": "add login"})
+	require.NoError(t, err)
+	assert.Contains(t, text, "Plan for add login")
+	assert.Contains(t, text, "`interactive_feedback`")
+	assert.Contains(t, text, `"previousUserRequest": "add login"`)
+
+	_, err = Render(tmpl, map[string]string{})
+	assert.Error(t, err)
+}
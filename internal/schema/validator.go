@@ -0,0 +1,200 @@
+package schema
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// FieldError describes a single value that does not match its schema
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+func (e FieldError) Error() string {
+	if e.Field == "" {
+		return e.Message
+	}
+	return fmt.Sprintf("%s: %s", e.Field, e.Message)
+}
+
+// Validate checks a decoded JSON value against a JSON schema.
+// It supports the subset used by tool input schemas: type, properties,
+// required, additionalProperties, items and enum.
+func Validate(schema map[string]interface{}, value interface{}) []FieldError {
+	var errs []FieldError
+	validate(schema, value, "", &errs)
+	return errs
+}
+
+// Summary joins field errors into a single message
+func Summary(errs []FieldError) string {
+	messages := make([]string, len(errs))
+	for i, err := range errs {
+		messages[i] = err.Error()
+	}
+	return strings.Join(messages, "; ")
+}
+
+func validate(schema map[string]interface{}, value interface{}, path string, errs *[]FieldError) {
+	if schema == nil {
+		return
+	}
+
+	if expected, ok := schema["type"].(string); ok {
+		if !matchesType(expected, value) {
+			*errs = append(*errs, FieldError{
+				Field:   path,
+				Message: fmt.Sprintf("expected %s, got %s", expected, typeName(value)),
+			})
+			return
+		}
+	}
+
+	if enum := toSlice(schema["enum"]); enum != nil {
+		found := false
+		for _, allowed := range enum {
+			if allowed == value {
+				found = true
+				break
+			}
+		}
+		if !found {
+			*errs = append(*errs, FieldError{
+				Field:   path,
+				Message: fmt.Sprintf("must be one of %v", enum),
+			})
+		}
+	}
+
+	switch typed := value.(type) {
+	case map[string]interface{}:
+		validateObject(schema, typed, path, errs)
+	case []interface{}:
+		items, ok := schema["items"].(map[string]interface{})
+		if !ok {
+			return
+		}
+		for i, item := range typed {
+			validate(items, item, fmt.Sprintf("%s[%d]", path, i), errs)
+		}
+	}
+}
+
+func validateObject(schema map[string]interface{}, object map[string]interface{}, path string, errs *[]FieldError) {
+	properties, _ := schema["properties"].(map[string]interface{})
+
+	for _, name := range toStrings(schema["required"]) {
+		if _, exists := object[name]; !exists {
+			*errs = append(*errs, FieldError{
+				Field:   joinPath(path, name),
+				Message: "is required",
+			})
+		}
+	}
+
+	// Sort keys so error order is stable
+	names := make([]string, 0, len(object))
+	for name := range object {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		propertySchema, known := properties[name].(map[string]interface{})
+		if !known {
+			if additional, ok := schema["additionalProperties"].(bool); ok && !additional {
+				*errs = append(*errs, FieldError{
+					Field:   joinPath(path, name),
+					Message: "unknown property",
+				})
+			}
+			continue
+		}
+		validate(propertySchema, object[name], joinPath(path, name), errs)
+	}
+}
+
+func matchesType(expected string, value interface{}) bool {
+	switch expected {
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "number":
+		_, ok := value.(float64)
+		return ok
+	case "integer":
+		number, ok := value.(float64)
+		return ok && number == math.Trunc(number)
+	case "object":
+		_, ok := value.(map[string]interface{})
+		return ok
+	case "array":
+		_, ok := value.([]interface{})
+		return ok
+	case "null":
+		return value == nil
+	}
+	return true
+}
+
+func typeName(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case float64:
+		return "number"
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+// toStrings accepts both Go literals ([]string) and decoded JSON ([]interface{})
+func toStrings(value interface{}) []string {
+	switch typed := value.(type) {
+	case []string:
+		return typed
+	case []interface{}:
+		result := make([]string, 0, len(typed))
+		for _, item := range typed {
+			if str, ok := item.(string); ok {
+				result = append(result, str)
+			}
+		}
+		return result
+	}
+	return nil
+}
+
+func toSlice(value interface{}) []interface{} {
+	switch typed := value.(type) {
+	case []interface{}:
+		return typed
+	case []string:
+		result := make([]interface{}, len(typed))
+		for i, item := range typed {
+			result[i] = item
+		}
+		return result
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package schema
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testSchema = map[string]interface{}{
+	"type": "object",
+	"properties": map[string]interface{}{
+		"prompt": map[string]interface{}{
+			"type": "string",
+		},
+		"timeout": map[string]interface{}{
+			"type": "integer",
+		},
+		"mode": map[string]interface{}{
+			"type": "string",
+			"enum": []string{"before", "parallel"},
+		},
+		"tags": map[string]interface{}{
+			"type":  "array",
+			"items": map[string]interface{}{"type": "string"},
+		},
+	},
+	"required":             []string{"prompt"},
+	"additionalProperties": false,
+}
+
+func decode(t *testing.T, raw string) map[string]interface{} {
+	var value map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(raw), &value))
+	return value
+}
+
+func TestValidate_Valid(t *testing.T) {
+	errs := Validate(testSchema, decode(t, `{"prompt": "hi", "timeout": 30, "mode": "before", "tags": ["a", "b"]}`))
+	assert.Empty(t, errs)
+}
+
+func TestValidate_MissingField(t *testing.T) {
+	errs := Validate(testSchema, decode(t, `{}`))
+	require.Len(t, errs, 1)
+	assert.Equal(t, "prompt", errs[0].Field)
+	assert.Equal(t, "is required", errs[0].Message)
+}
+
+func TestValidate_WrongTypes(t *testing.T) {
+	errs := Validate(testSchema, decode(t, `{"prompt": 42, "timeout": 1.5, "tags": ["a", true]}`))
+	require.Len(t, errs, 3)
+	assert.Equal(t, "prompt", errs[0].Field)
+	assert.Equal(t, "expected string, got number", errs[0].Message)
+	assert.Equal(t, "tags[1]", errs[1].Field)
+	assert.Equal(t, "expected string, got boolean", errs[1].Message)
+	assert.Equal(t, "timeout", errs[2].Field)
+	assert.Equal(t, "expected integer, got number", errs[2].Message)
+}
+
+func TestValidate_UnknownProperties(t *testing.T) {
+	errs := Validate(testSchema, decode(t, `{"prompt": "hi", "extra": 1}`))
+	require.Len(t, errs, 1)
+	assert.Equal(t, "extra", errs[0].Field)
+	assert.Equal(t, "unknown property", errs[0].Message)
+
+	// Unknown properties are allowed unless additionalProperties is false
+	open := map[string]interface{}{"type": "object"}
+	assert.Empty(t, Validate(open, decode(t, `{"extra": 1}`)))
+}
+
+func TestValidate_Enum(t *testing.T) {
+	errs := Validate(testSchema, decode(t, `{"prompt": "hi", "mode": "later"}`))
+	require.Len(t, errs, 1)
+	assert.Equal(t, "mode", errs[0].Field)
+}
+
+func TestValidate_JSONDecodedSchema(t *testing.T) {
+	schema := decode(t, `{"type": "object", "properties": {"name": {"type": "string"}}, "required": ["name"]}`)
+	errs := Validate(schema, decode(t, `{}`))
+	require.Len(t, errs, 1)
+	assert.Equal(t, "name: is required", errs[0].Error())
+}
+
+func TestSummary(t *testing.T) {
+	errs := []FieldError{
+		{Field: "prompt", Message: "is required"},
+		{Field: "extra", Message: "unknown property"},
+	}
+	assert.Equal(t, "prompt: is required; extra: unknown property", Summary(errs))
+}
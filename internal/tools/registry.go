@@ -0,0 +1,212 @@
+package tools
+
+import (
+	"encoding/base64"
+	"fmt"
+	"sync"
+)
+
+// Call carries the arguments of a single tools/call request
+type Call struct {
+	Name      string
+	Arguments map[string]interface{}
+	// OnProgress is set when the client asked for progress notifications
+	OnProgress func(progress, total float64, message string)
+}
+
+// ReportProgress sends a progress notification if the client asked for them.
+// A total of 0 means the total is unknown.
+func (c *Call) ReportProgress(progress, total float64, message string) {
+	if c.OnProgress != nil {
+		c.OnProgress(progress, total, message)
+	}
+}
+
+// Result is the MCP tools/call result
+type Result struct {
+	Content []map[string]interface{} `json:"content"`
+	IsError bool                     `json:"isError,omitempty"`
+}
+
+// Error is returned by handlers that need a specific JSON-RPC error code
+type Error struct {
+	Code    int
+	Message string
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// InvalidParams reports a problem with the tool arguments (JSON-RPC -32602)
+func InvalidParams(format string, args ...interface{}) *Error {
+	return &Error{
+		Code:    -32602,
+		Message: fmt.Sprintf(format, args...),
+	}
+}
+
+// Handler runs a tool call
+type Handler func(call *Call) (*Result, error)
+
+// Tool is a registered MCP tool
+type Tool struct {
+	Name        string                 `json:"name"`
+	Description string                 `json:"description"`
+	InputSchema map[string]interface{} `json:"inputSchema"`
+	Handler     Handler                `json:"-"`
+}
+
+// Registry holds the tools exposed by the server and which of them are enabled
+type Registry struct {
+	tools         map[string]*Tool
+	order         []string
+	disabled      map[string]bool
+	onListChanged func()
+	mutex         sync.RWMutex
+}
+
+func NewRegistry() *Registry {
+	return &Registry{
+		tools:    make(map[string]*Tool),
+		disabled: make(map[string]bool),
+	}
+}
+
+// Register adds a tool. Tool names must be unique.
+func (r *Registry) Register(tool Tool) error {
+	if tool.Name == "" {
+		return fmt.Errorf("tool name is required")
+	}
+	if tool.Handler == nil {
+		return fmt.Errorf("tool %s has no handler", tool.Name)
+	}
+
+	r.mutex.Lock()
+	if _, exists := r.tools[tool.Name]; exists {
+		r.mutex.Unlock()
+		return fmt.Errorf("tool %s is already registered", tool.Name)
+	}
+	r.tools[tool.Name] = &tool
+	r.order = append(r.order, tool.Name)
+	callback := r.onListChanged
+	r.mutex.Unlock()
+
+	if callback != nil {
+		callback()
+	}
+	return nil
+}
+
+// List returns the enabled tools in registration order
+func (r *Registry) List() []Tool {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	tools := make([]Tool, 0, len(r.order))
+	for _, name := range r.order {
+		if r.disabled[name] {
+			continue
+		}
+		tools = append(tools, *r.tools[name])
+	}
+	return tools
+}
+
+// Lookup returns an enabled tool by name
+func (r *Registry) Lookup(name string) (*Tool, bool) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	tool, exists := r.tools[name]
+	if !exists || r.disabled[name] {
+		return nil, false
+	}
+	return tool, true
+}
+
+// SetDisabled replaces the set of disabled tools and reports whether the
+// visible tool list changed. The list-changed callback fires on change.
+func (r *Registry) SetDisabled(names []string) bool {
+	disabled := make(map[string]bool, len(names))
+	for _, name := range names {
+		disabled[name] = true
+	}
+
+	r.mutex.Lock()
+	changed := false
+	for name := range r.tools {
+		if disabled[name] != r.disabled[name] {
+			changed = true
+			break
+		}
+	}
+	r.disabled = disabled
+	callback := r.onListChanged
+	r.mutex.Unlock()
+
+	if changed && callback != nil {
+		callback()
+	}
+	return changed
+}
+
+// SetOnListChanged sets the callback used to emit notifications/tools/list_changed
+func (r *Registry) SetOnListChanged(callback func()) {
+	r.mutex.Lock()
+	r.onListChanged = callback
+	r.mutex.Unlock()
+}
+
+// TextResult builds a result with a single text content block
+func TextResult(text string) *Result {
+	return &Result{
+		Content: []map[string]interface{}{
+			{
+				"type": "text",
+				"text": text,
+			},
+		},
+	}
+}
+
+// ErrorResult builds a text result flagged as a tool error, which the model
+// sees as a failed call rather than a protocol error
+func ErrorResult(text string) *Result {
+	result := TextResult(text)
+	result.IsError = true
+	return result
+}
+
+// ImageContent builds an image content block
+func ImageContent(data []byte, mimeType string) map[string]interface{} {
+	return map[string]interface{}{
+		"type":     "image",
+		"data":     base64.StdEncoding.EncodeToString(data),
+		"mimeType": mimeType,
+	}
+}
+
+// TextResourceContent builds an embedded resource block holding text
+func TextResourceContent(uri, mimeType, text string) map[string]interface{} {
+	return map[string]interface{}{
+		"type": "resource",
+		"resource": map[string]interface{}{
+			"uri":      uri,
+			"mimeType": mimeType,
+			"text":     text,
+		},
+	}
+}
+
+// BlobResourceContent builds an embedded resource block holding binary data
+func BlobResourceContent(uri, mimeType string, data []byte) map[string]interface{} {
+	return map[string]interface{}{
+		"type": "resource",
+		"resource": map[string]interface{}{
+			"uri":      uri,
+			"mimeType": mimeType,
+			"blob":     base64.StdEncoding.EncodeToString(data),
+		},
+	}
+}
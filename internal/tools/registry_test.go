@@ -0,0 +1,106 @@
+package tools
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func noopHandler(call *Call) (*Result, error) {
+	return TextResult(call.Name), nil
+}
+
+func TestRegistry_RegisterAndList(t *testing.T) {
+	registry := NewRegistry()
+	require.NoError(t, registry.Register(Tool{Name: "first", Handler: noopHandler}))
+	require.NoError(t, registry.Register(Tool{Name: "second", Handler: noopHandler}))
+
+	tools := registry.List()
+	require.Len(t, tools, 2)
+	assert.Equal(t, "first", tools[0].Name)
+	assert.Equal(t, "second", tools[1].Name)
+
+	tool, ok := registry.Lookup("second")
+	require.True(t, ok)
+	result, err := tool.Handler(&Call{Name: "second"})
+	require.NoError(t, err)
+	assert.Equal(t, "second", result.Content[0]["text"])
+}
+
+func TestRegistry_RegisterInvalid(t *testing.T) {
+	registry := NewRegistry()
+	assert.Error(t, registry.Register(Tool{Handler: noopHandler}))
+	assert.Error(t, registry.Register(Tool{Name: "missing-handler"}))
+
+	require.NoError(t, registry.Register(Tool{Name: "dup", Handler: noopHandler}))
+	assert.Error(t, registry.Register(Tool{Name: "dup", Handler: noopHandler}))
+}
+
+func TestRegistry_SetDisabled(t *testing.T) {
+	registry := NewRegistry()
+	require.NoError(t, registry.Register(Tool{Name: "first", Handler: noopHandler}))
+	require.NoError(t, registry.Register(Tool{Name: "second", Handler: noopHandler}))
+
+	notifications := 0
+	registry.SetOnListChanged(func() {
+		notifications++
+	})
+
+	assert.True(t, registry.SetDisabled([]string{"first"}))
+	assert.Equal(t, 1, notifications)
+
+	_, ok := registry.Lookup("first")
+	assert.False(t, ok)
+	tools := registry.List()
+	require.Len(t, tools, 1)
+	assert.Equal(t, "second", tools[0].Name)
+
+	// Same set again does not notify
+	assert.False(t, registry.SetDisabled([]string{"first"}))
+	assert.Equal(t, 1, notifications)
+
+	// The new set replaces the old one, so this re-enables "first"
+	assert.True(t, registry.SetDisabled([]string{"unknown"}))
+	assert.Equal(t, 2, notifications)
+	assert.Len(t, registry.List(), 2)
+
+	// Unknown names alone do not change the visible list
+	assert.False(t, registry.SetDisabled([]string{"unknown", "other"}))
+	assert.Equal(t, 2, notifications)
+	assert.Len(t, registry.List(), 2)
+}
+
+func TestCall_ReportProgress(t *testing.T) {
+	// Without a progress token nothing is sent
+	(&Call{Name: "silent"}).ReportProgress(1, 0, "ignored")
+
+	var messages []string
+	call := &Call{
+		Name: "noisy",
+		OnProgress: func(progress, total float64, message string) {
+			messages = append(messages, message)
+		},
+	}
+	call.ReportProgress(1, 2, "half")
+	call.ReportProgress(2, 2, "done")
+	assert.Equal(t, []string{"half", "done"}, messages)
+}
+
+func TestErrorResult(t *testing.T) {
+	result := ErrorResult("failed")
+	assert.True(t, result.IsError)
+	assert.Equal(t, "failed", result.Content[0]["text"])
+}
+
+func TestContentBlocks(t *testing.T) {
+	image := ImageContent([]byte("png"), "image/png")
+	assert.Equal(t, map[string]interface{}{"type": "image", "data": "cG5n", "mimeType": "image/png"}, image)
+
+	text := TextResourceContent("file:///tmp/notes.md", "text/markdown", "# Notes")
+	assert.Equal(t, "resource", text["type"])
+	assert.Equal(t, map[string]interface{}{"uri": "file:///tmp/notes.md", "mimeType": "text/markdown", "text": "# Notes"}, text["resource"])
+
+	blob := BlobResourceContent("file:///tmp/a.pdf", "application/pdf", []byte{0, 1})
+	assert.Equal(t, map[string]interface{}{"uri": "file:///tmp/a.pdf", "mimeType": "application/pdf", "blob": "AAE="}, blob["resource"])
+}
@@ -0,0 +1,160 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Conversation entry kinds
+const (
+	EntryKindText          = "text"
+	EntryKindChoice        = "choice"
+	EntryKindForm          = "form"
+	EntryKindCommandResult = "command_result"
+	EntryKindAttachment    = "attachment"
+	EntryKindSystem        = "system"
+	EntryKindPlan          = "plan"
+)
+
+// EntryPayload is the structured part of a conversation entry. Payloads are
+// pointers to the type matching the entry kind, e.g. *ChoicePayload.
+type EntryPayload interface {
+	EntryKind() string
+}
+
+// ChoicePayload records a question answered by picking options
+type ChoicePayload struct {
+	Question string   `json:"question,omitempty"`
+	Options  []string `json:"options"`
+	Selected []string `json:"selected"`
+}
+
+// FormField is one answered field of a form
+type FormField struct {
+	Name  string `json:"name"`
+	Label string `json:"label,omitempty"`
+	Value string `json:"value"`
+}
+
+// FormPayload records the answers to a form
+type FormPayload struct {
+	Title  string      `json:"title,omitempty"`
+	Fields []FormField `json:"fields"`
+}
+
+// CommandResultPayload records a finished project command
+type CommandResultPayload struct {
+	Name       string `json:"name,omitempty"` // command profile
+	Command    string `json:"command"`
+	ExitCode   *int   `json:"exit_code,omitempty"`
+	Signal     string `json:"signal,omitempty"`
+	TimedOut   bool   `json:"timed_out,omitempty"`
+	DurationMs int64  `json:"duration_ms,omitempty"`
+	Output     string `json:"output,omitempty"` // plain text
+	Error      string `json:"error,omitempty"`  // set when the command could not be run
+}
+
+// Attachment describes a file or image attached to a message
+type Attachment struct {
+	Name     string `json:"name"`
+	MimeType string `json:"mime_type"`
+	Size     int64  `json:"size"`
+	Path     string `json:"path,omitempty"` // where the file was attached from
+}
+
+// AttachmentPayload records files attached to a message
+type AttachmentPayload struct {
+	Attachments []Attachment `json:"attachments"`
+}
+
+// SystemPayload records an event of the feedback session itself
+type SystemPayload struct {
+	Event string `json:"event"` // e.g. "history_cleared"
+}
+
+// UnknownPayload keeps the payload of a kind this version does not know, or
+// one it cannot read, so that it survives a load and save
+type UnknownPayload struct {
+	Kind string
+	Data json.RawMessage
+}
+
+func (*ChoicePayload) EntryKind() string        { return EntryKindChoice }
+func (*FormPayload) EntryKind() string          { return EntryKindForm }
+func (*CommandResultPayload) EntryKind() string { return EntryKindCommandResult }
+func (*AttachmentPayload) EntryKind() string    { return EntryKindAttachment }
+func (*SystemPayload) EntryKind() string        { return EntryKindSystem }
+func (*Plan) EntryKind() string                 { return EntryKindPlan }
+func (p *UnknownPayload) EntryKind() string     { return p.Kind }
+
+func (p *UnknownPayload) MarshalJSON() ([]byte, error) {
+	return p.Data, nil
+}
+
+// newPayload returns an empty payload for a kind, or nil if the kind is not known
+func newPayload(kind string) EntryPayload {
+	switch kind {
+	case EntryKindChoice:
+		return &ChoicePayload{}
+	case EntryKindForm:
+		return &FormPayload{}
+	case EntryKindCommandResult:
+		return &CommandResultPayload{}
+	case EntryKindAttachment:
+		return &AttachmentPayload{}
+	case EntryKindSystem:
+		return &SystemPayload{}
+	case EntryKindPlan:
+		return &Plan{}
+	}
+	return nil
+}
+
+type conversationEntryJSON ConversationEntry
+
+// MarshalJSON fills in the kind from the payload and rejects payloads that
+// do not match the kind
+func (e ConversationEntry) MarshalJSON() ([]byte, error) {
+	entry := conversationEntryJSON(e)
+	if entry.Payload != nil {
+		if entry.Kind == "" {
+			entry.Kind = entry.Payload.EntryKind()
+		} else if entry.Kind != entry.Payload.EntryKind() {
+			return nil, fmt.Errorf("conversation entry %s has kind %q but a %q payload", e.ID, entry.Kind, entry.Payload.EntryKind())
+		}
+	}
+	return json.Marshal(entry)
+}
+
+// UnmarshalJSON decodes the payload into the type matching the kind. Unknown
+// kinds and malformed payloads are kept as an UnknownPayload.
+func (e *ConversationEntry) UnmarshalJSON(data []byte) error {
+	var entry struct {
+		conversationEntryJSON
+		Payload json.RawMessage `json:"payload"`
+		// Plan entries were first saved with the plan in their own field
+		LegacyPlan json.RawMessage `json:"plan"`
+	}
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return err
+	}
+
+	*e = ConversationEntry(entry.conversationEntryJSON)
+	if len(entry.Payload) == 0 && len(entry.LegacyPlan) > 0 && string(entry.LegacyPlan) != "null" {
+		e.Kind = EntryKindPlan
+		entry.Payload = entry.LegacyPlan
+	}
+	if len(entry.Payload) == 0 || string(entry.Payload) == "null" {
+		return nil
+	}
+
+	// A payload that cannot be read is kept as is rather than failing the
+	// whole history, which would lose the rest of the project config
+	payload := newPayload(e.Kind)
+	if payload == nil || json.Unmarshal(entry.Payload, payload) != nil {
+		e.Payload = &UnknownPayload{Kind: e.Kind, Data: entry.Payload}
+		return nil
+	}
+	e.Payload = payload
+	return nil
+}
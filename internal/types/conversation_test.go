@@ -0,0 +1,104 @@
+package types
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConversationEntry_PayloadRoundTrip(t *testing.T) {
+	exitCode := 1
+	original := 0
+	payloads := []EntryPayload{
+		&ChoicePayload{Question: "Which database?", Options: []string{"sqlite", "postgres"}, Selected: []string{"postgres"}},
+		&FormPayload{Title: "Release", Fields: []FormField{{Name: "version", Label: "Version", Value: "1.2.0"}}},
+		&CommandResultPayload{Name: "test", Command: "go test ./...", ExitCode: &exitCode, DurationMs: 1200, Output: "FAIL\n"},
+		&AttachmentPayload{Attachments: []Attachment{{Name: "screenshot.png", MimeType: "image/png", Size: 2048}}},
+		&SystemPayload{Event: "history_cleared"},
+		&Plan{Proposed: []string{"a"}, Steps: []PlanStep{{Text: "a", Original: &original, Approved: true}}, Verdict: VerdictApproved},
+	}
+
+	for _, payload := range payloads {
+		t.Run(payload.EntryKind(), func(t *testing.T) {
+			entry := ConversationEntry{
+				ID:        "entry",
+				Timestamp: time.Date(2025, 10, 19, 1, 0, 0, 0, time.UTC),
+				Role:      "user",
+				Content:   "summary",
+				Payload:   payload,
+			}
+
+			data, err := json.Marshal(entry)
+			require.NoError(t, err)
+
+			var decoded ConversationEntry
+			require.NoError(t, json.Unmarshal(data, &decoded))
+
+			entry.Kind = payload.EntryKind() // Filled in from the payload
+			assert.Equal(t, entry, decoded)
+		})
+	}
+}
+
+func TestConversationEntry_TextHasNoPayload(t *testing.T) {
+	data, err := json.Marshal(ConversationEntry{ID: "entry", Role: "assistant", Content: "Hello"})
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "kind")
+	assert.NotContains(t, string(data), "payload")
+
+	var decoded ConversationEntry
+	require.NoError(t, json.Unmarshal([]byte(`{"id":"old","role":"user","content":"Hi","is_current":false}`), &decoded))
+	assert.Empty(t, decoded.Kind)
+	assert.Nil(t, decoded.Payload)
+}
+
+func TestConversationEntry_UnknownKindSurvives(t *testing.T) {
+	input := `{"id":"e","timestamp":"2025-10-19T01:00:00Z","role":"user","content":"x","is_current":false,"kind":"poll","payload":{"votes":3}}`
+
+	var decoded ConversationEntry
+	require.NoError(t, json.Unmarshal([]byte(input), &decoded))
+	assert.Equal(t, &UnknownPayload{Kind: "poll", Data: json.RawMessage(`{"votes":3}`)}, decoded.Payload)
+
+	data, err := json.Marshal(decoded)
+	require.NoError(t, err)
+	assert.JSONEq(t, input, string(data))
+}
+
+func TestConversationEntry_LegacyPlanField(t *testing.T) {
+	input := `{"id":"e","timestamp":"2025-10-19T01:00:00Z","role":"user","content":"Plan approved","is_current":false,"kind":"plan","plan":{"proposed":["a"],"steps":[{"text":"a","approved":true}],"verdict":"approved"}}`
+
+	var decoded ConversationEntry
+	require.NoError(t, json.Unmarshal([]byte(input), &decoded))
+	assert.Equal(t, EntryKindPlan, decoded.Kind)
+	require.IsType(t, &Plan{}, decoded.Payload)
+	plan := decoded.Payload.(*Plan)
+	assert.Equal(t, []string{"a"}, plan.Proposed)
+	assert.Equal(t, "approved", plan.Verdict)
+
+	// It is saved in the current shape
+	data, err := json.Marshal(decoded)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"payload":{"proposed":["a"]`)
+	assert.NotContains(t, string(data), `"plan":{`)
+}
+
+func TestConversationEntry_Errors(t *testing.T) {
+	_, err := json.Marshal(ConversationEntry{ID: "e", Kind: EntryKindForm, Payload: &SystemPayload{Event: "x"}})
+	assert.Error(t, err)
+
+}
+
+func TestConversationEntry_MalformedPayloadSurvives(t *testing.T) {
+	input := `{"id":"e","timestamp":"2025-10-19T01:00:00Z","role":"user","content":"x","is_current":false,"kind":"choice","payload":{"options":"not a list"}}`
+
+	var decoded ConversationEntry
+	require.NoError(t, json.Unmarshal([]byte(input), &decoded))
+	assert.Equal(t, &UnknownPayload{Kind: EntryKindChoice, Data: json.RawMessage(`{"options":"not a list"}`)}, decoded.Payload)
+
+	data, err := json.Marshal(decoded)
+	require.NoError(t, err)
+	assert.JSONEq(t, input, string(data))
+}
@@ -1,13 +1,64 @@
 package types
 
-import "time"
+import (
+	"sync/atomic"
+	"time"
+)
 
 // ProjectConfig represents configuration for a specific project
 type ProjectConfig struct {
-	RunCommand              string                `json:"run_command"`
-	ExecuteAutomatically    bool                  `json:"execute_automatically"`
-	CommandSectionVisible   bool                  `json:"command_section_visible"`
-	ConversationHistory     []ConversationEntry   `json:"conversation_history"`
+	RunCommand            string              `json:"run_command"`
+	ExecuteAutomatically  bool                `json:"execute_automatically"`
+	CommandSectionVisible bool                `json:"command_section_visible"`
+	ConversationHistory   []ConversationEntry `json:"conversation_history"`
+	DisabledTools         []string            `json:"disabled_tools,omitempty"`
+	UsePTY                bool                `json:"use_pty,omitempty"` // run the command in a pseudo-terminal (Linux only)
+	// Seconds to wait after SIGINT and after SIGTERM when stopping the command; 0 skips the stage
+	StopInterruptSeconds *float64 `json:"stop_interrupt_seconds,omitempty"`
+	StopTerminateSeconds *float64 `json:"stop_terminate_seconds,omitempty"`
+	// Shell runs the command, e.g. "zsh" or "pwsh"; defaults to bash (cmd on Windows)
+	Shell      string            `json:"shell,omitempty"`
+	LoginShell bool              `json:"login_shell,omitempty"` // load the user's profile (PATH, nvm, venv, ...)
+	Env        map[string]string `json:"env,omitempty"`
+	EnvFile    string            `json:"env_file,omitempty"`    // .env file, relative to the project
+	WorkingDir string            `json:"working_dir,omitempty"` // subdirectory of the project to run in
+	// Commands are named commands (test, lint, ...); run_command is used when empty
+	Commands []CommandProfile `json:"commands,omitempty"`
+}
+
+// CommandProfile is a named project command
+type CommandProfile struct {
+	Name    string            `json:"name"`
+	Command string            `json:"command"`
+	Cwd     string            `json:"cwd,omitempty"` // overrides working_dir
+	Env     map[string]string `json:"env,omitempty"` // added to the project's env
+	AutoRun bool              `json:"auto_run,omitempty"`
+	Default bool              `json:"default,omitempty"`
+}
+
+// UserConfig holds user-level settings shared by all projects
+type UserConfig struct {
+	AllowedRoots []string `json:"allowed_roots,omitempty"` // project directories must sit inside one of these
+	StrictRoots  bool     `json:"strict_roots,omitempty"`  // reject projects outside the client's MCP roots
+	// GitignorePolicy is "gitignore" (default), "exclude" for .git/info/exclude, or "off"
+	GitignorePolicy string `json:"gitignore_policy,omitempty"`
+	// ConfigStorage is "project" (default) or "user" to keep project state out of the repository
+	ConfigStorage string `json:"config_storage,omitempty"`
+	// RequireCommandApproval asks before running a project command that is new or has changed
+	RequireCommandApproval bool           `json:"require_command_approval,omitempty"`
+	ApprovedCommands       []string       `json:"approved_commands,omitempty"` // hashes of approved project commands
+	CommandLimits          *CommandLimits `json:"command_limits,omitempty"`
+}
+
+// CommandLimits restricts project commands; zero values mean no limit
+type CommandLimits struct {
+	TimeoutSeconds float64  `json:"timeout_seconds,omitempty"`
+	MaxOutputBytes int      `json:"max_output_bytes,omitempty"`
+	CPUSeconds     int      `json:"cpu_seconds,omitempty"`
+	MemoryMB       int      `json:"memory_mb,omitempty"`
+	OpenFiles      int      `json:"open_files,omitempty"`
+	ClearEnv       bool     `json:"clear_env,omitempty"`     // start from an empty environment
+	EnvAllowlist   []string `json:"env_allowlist,omitempty"` // inherited variables kept with clear_env
 }
 
 // ConversationEntry represents a single message in the conversation
@@ -17,20 +68,150 @@ type ConversationEntry struct {
 	Role      string    `json:"role"` // "user" or "assistant"
 	Content   string    `json:"content"`
 	IsCurrent bool      `json:"is_current"`
+	// Kind says what Payload holds; empty means text. Content keeps a text
+	// form of structured entries for readers that do not know the kind.
+	Kind    string       `json:"kind,omitempty"`
+	Payload EntryPayload `json:"payload,omitempty"`
 }
 
 // CommandHandle represents a running command process
 type CommandHandle struct {
 	PID       int
 	StartTime time.Time
-	IsRunning bool
+	running   atomic.Bool
 	Output    chan string
 	Done      chan error
+	Result    *CommandResult // set before Done is closed
+	Chunks    OutputSource   // ordered, sequence-numbered view of the output
+}
+
+// IsRunning reports whether the process has not exited yet. It is safe to
+// call from any goroutine.
+func (h *CommandHandle) IsRunning() bool {
+	return h.running.Load()
+}
+
+// SetRunning records whether the process is running
+func (h *CommandHandle) SetRunning(running bool) {
+	h.running.Store(running)
+}
+
+// Output streams recorded in OutputChunk.Stream
+const (
+	StreamStdout = "stdout"
+	StreamStderr = "stderr"
+	StreamSystem = "system" // markers added by the executor, e.g. dropped lines
+)
+
+// OutputChunk is one piece of command output. Data holds a whole line
+// including its newline unless Partial is set, in which case the line
+// continues in the next chunk of the same stream (or ended without a newline).
+type OutputChunk struct {
+	Seq     uint64 `json:"seq"`
+	Stream  string `json:"stream"`
+	Data    string `json:"data"`
+	Partial bool   `json:"partial,omitempty"`
+}
+
+// OutputSource gives access to buffered command output
+type OutputSource interface {
+	// Since returns the buffered chunks with a sequence number >= seq. Chunks
+	// that were evicted are reported by a single StreamSystem marker.
+	Since(seq uint64) []OutputChunk
+}
+
+// CommandResult describes a finished command
+type CommandResult struct {
+	Command       string              `json:"command"`
+	ExitCode      int                 `json:"exit_code"`               // -1 if the process did not exit normally
+	Signal        string              `json:"signal,omitempty"`        // signal that terminated the process
+	TerminatedBy  string              `json:"terminated_by,omitempty"` // last stop signal sent by the executor, if it was stopped
+	TimedOut      bool                `json:"timed_out,omitempty"`     // stopped because it ran past its timeout
+	StartTime     time.Time           `json:"start_time"`
+	EndTime       time.Time           `json:"end_time"`
+	DurationMs    int64               `json:"duration_ms"`
+	BytesCaptured int64               `json:"bytes_captured"` // total output bytes, including any truncated part
+	Truncated     bool                `json:"truncated"`      // Output holds only the first part of the output
+	Output        string              `json:"output,omitempty"`
+	Error         string              `json:"error,omitempty"` // set when the process could not be waited on
+	Environment   *CommandEnvironment `json:"environment,omitempty"`
+}
+
+// CommandEnvironment describes how a command was started. Values loaded from
+// an env file are not repeated, since they often hold secrets.
+type CommandEnvironment struct {
+	Shell      []string          `json:"shell"` // interpreter and flags preceding the command
+	WorkingDir string            `json:"working_dir"`
+	Path       string            `json:"path,omitempty"`      // PATH given to the shell, before any login profile
+	Cleared    bool              `json:"cleared,omitempty"`   // started from an allow-listed environment
+	Set        map[string]string `json:"set,omitempty"`       // variables from the project's env setting
+	FromFile   []string          `json:"from_file,omitempty"` // names of variables loaded from the env file
+}
+
+// CommandRunSummary reports one command profile run during a feedback round-trip
+type CommandRunSummary struct {
+	Name       string `json:"name"`
+	Command    string `json:"command"`
+	ExitCode   *int   `json:"exit_code,omitempty"`
+	DurationMs int64  `json:"duration_ms,omitempty"`
+	Error      string `json:"error,omitempty"` // set when the command could not be run
 }
 
 // FeedbackResult represents the final output
 type FeedbackResult struct {
-	CommandLogs         string                `json:"command_logs"`
-	InteractiveFeedback string                `json:"interactive_feedback"`
-	ConversationHistory []ConversationEntry   `json:"conversation_history"`
+	CommandLogs         string              `json:"command_logs"`
+	CommandExitCode     *int                `json:"command_exit_code,omitempty"`
+	CommandDurationMs   int64               `json:"command_duration_ms,omitempty"`
+	CommandEnvironment  *CommandEnvironment `json:"command_environment,omitempty"`
+	CommandRuns         []CommandRunSummary `json:"command_runs,omitempty"` // one per profile when several ran
+	InteractiveFeedback string              `json:"interactive_feedback"`
+	Attachments         []Attachment        `json:"attachments,omitempty"`       // returned as content blocks after this result
+	AttachmentErrors    []string            `json:"attachment_errors,omitempty"` // attachments that were skipped and why
+	ConversationHistory []ConversationEntry `json:"conversation_history"`
+}
+
+// Review verdicts for a hunk and for a whole diff
+const (
+	VerdictApproved  = "approved"
+	VerdictRejected  = "rejected"
+	VerdictPending   = "pending"   // not decided on by the user
+	VerdictPartial   = "partial"   // some hunks approved, others not
+	VerdictCancelled = "cancelled" // the review window was closed without submitting
+)
+
+// HunkReview is the user's decision on one hunk of a reviewed diff
+type HunkReview struct {
+	File    string `json:"file"`
+	Hunk    int    `json:"hunk"` // index of the hunk within the file, from 0
+	Header  string `json:"header"`
+	Verdict string `json:"verdict"`
+	Comment string `json:"comment,omitempty"`
+}
+
+// DiffReviewResult is the structured verdict of request_diff_review
+type DiffReviewResult struct {
+	Verdict       string       `json:"verdict"`
+	Comment       string       `json:"comment,omitempty"` // overall comment
+	ApprovedHunks []HunkReview `json:"approved_hunks"`
+	RejectedHunks []HunkReview `json:"rejected_hunks"`
+	PendingHunks  []HunkReview `json:"pending_hunks,omitempty"`
+}
+
+// PlanStep is one step of a plan after the user's edits
+type PlanStep struct {
+	Text     string `json:"text"`
+	Original *int   `json:"original,omitempty"` // index of the proposed step it came from; nil for added steps
+	Edited   bool   `json:"edited,omitempty"`
+	Approved bool   `json:"approved"`
+}
+
+// Plan is the result of approve_plan: the proposed steps and the plan as
+// the user left it
+type Plan struct {
+	Title    string     `json:"title,omitempty"`
+	Proposed []string   `json:"proposed"`
+	Steps    []PlanStep `json:"steps"`
+	Removed  []int      `json:"removed,omitempty"` // indexes of proposed steps the user deleted
+	Verdict  string     `json:"verdict"`           // approved, partial, rejected or cancelled
+	Comment  string     `json:"comment,omitempty"`
 }
@@ -92,3 +92,34 @@ func TestFeedbackResult_JSONSerialization(t *testing.T) {
 	assert.Equal(t, result.InteractiveFeedback, decoded.InteractiveFeedback)
 	assert.Len(t, decoded.ConversationHistory, 1)
 }
+
+func TestCommandResult_JSONSerialization(t *testing.T) {
+	start := time.Now()
+	result := &CommandResult{
+		Command:       "make test",
+		ExitCode:      -1,
+		Signal:        "SIGTERM",
+		StartTime:     start,
+		EndTime:       start.Add(1500 * time.Millisecond),
+		DurationMs:    1500,
+		BytesCaptured: 2048,
+		Truncated:     true,
+		Output:        "partial output",
+	}
+
+	data, err := json.Marshal(result)
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), `"exit_code":-1`)
+	assert.Contains(t, string(data), `"signal":"SIGTERM"`)
+
+	var decoded CommandResult
+	err = json.Unmarshal(data, &decoded)
+	assert.NoError(t, err)
+	assert.Equal(t, result.Command, decoded.Command)
+	assert.Equal(t, result.ExitCode, decoded.ExitCode)
+	assert.Equal(t, result.Signal, decoded.Signal)
+	assert.Equal(t, result.DurationMs, decoded.DurationMs)
+	assert.Equal(t, result.BytesCaptured, decoded.BytesCaptured)
+	assert.True(t, decoded.Truncated)
+	assert.True(t, result.EndTime.Equal(decoded.EndTime))
+}
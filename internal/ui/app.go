@@ -13,7 +13,9 @@ import (
 	"fyne.io/fyne/v2/widget"
 
 	"interactive-feedback-mcp/internal/config"
+	"interactive-feedback-mcp/internal/conversation"
 	"interactive-feedback-mcp/internal/executor"
+	"interactive-feedback-mcp/internal/export"
 	"interactive-feedback-mcp/internal/types"
 )
 
@@ -25,11 +27,17 @@ type FeedbackApp struct {
 	configManager      *config.ConfigManager
 	commandExecutor    *executor.CommandExecutor
 	currentHandle      *types.CommandHandle
+	projectConfig      *types.ProjectConfig
+	commandLimits      *types.CommandLimits
+	selectedProfile    types.CommandProfile
+	consoleLog         strings.Builder // raw command output, as printed
 
 	// UI Components
+	profileSelect      *widget.Select
 	commandEntry       *widget.Entry
 	runButton          *widget.Button
-	consoleText        *widget.Entry
+	consoleText        *widget.RichText
+	consoleScroll      *container.Scroll
 	feedbackText       *widget.Entry
 	submitButton       *widget.Button
 	commandSection     *widget.Card
@@ -76,34 +84,42 @@ func (fa *FeedbackApp) createUI() {
 	fa.runButton = widget.NewButton("Run", fa.runCommand)
 	fa.runButton.Importance = widget.HighImportance
 
-	commandContainer := container.NewBorder(nil, nil, nil, fa.runButton, fa.commandEntry)
+	// Picker for the project's named commands, hidden for a single run_command
+	fa.profileSelect = widget.NewSelect(nil, fa.selectProfile)
+	fa.profileSelect.PlaceHolder = "Command"
+	fa.profileSelect.Hide()
+
+	commandContainer := container.NewBorder(nil, nil, fa.profileSelect, fa.runButton, fa.commandEntry)
 
 	fa.commandSection = widget.NewCard("Command", "", commandContainer)
 
 	// Console Section
-	fa.consoleText = widget.NewMultiLineEntry()
-	fa.consoleText.SetText("")
-	fa.consoleText.Disable()
+	fa.consoleText = widget.NewRichText()
 	fa.consoleText.Wrapping = fyne.TextWrapWord
+	fa.consoleScroll = container.NewVScroll(fa.consoleText)
+	fa.consoleScroll.SetMinSize(fyne.NewSize(0, 150))
 
 	clearButton := widget.NewButton("Clear", func() {
-		fa.consoleText.SetText("")
+		fa.consoleLog.Reset()
+		fa.consoleText.Segments = nil
+		fa.consoleText.Refresh()
 	})
 
-	consoleContainer := container.NewBorder(nil, clearButton, nil, nil, fa.consoleText)
+	consoleContainer := container.NewBorder(nil, clearButton, nil, nil, fa.consoleScroll)
 	consoleCard := widget.NewCard("Console", "", consoleContainer)
 
 	// Conversation History Section (NEW)
-	fa.conversationSection = NewConversationSection()
+	fa.conversationSection = NewConversationSection(fa.projectDirectory)
 	fa.conversationSection.SetOnCopy(fa.copyToClipboard)
+	fa.conversationSection.SetOnCopyCode(fa.copyCodeToClipboard)
+	fa.conversationSection.SetOnSave(fa.saveConversation)
 	fa.conversationSection.SetOnClear(fa.clearConversationHistory)
 
 	// Add initial conversation entry
 	fa.conversationSection.AddEntry("assistant", fa.prompt)
 
 	// Feedback Section
-	promptLabel := widget.NewLabel(fa.prompt)
-	promptLabel.Wrapping = fyne.TextWrapWord
+	promptView := markdownView(fa.prompt, fa.copyCodeToClipboard)
 
 	fa.feedbackText = widget.NewMultiLineEntry()
 	fa.feedbackText.SetPlaceHolder("Enter your feedback here...")
@@ -113,7 +129,7 @@ func (fa *FeedbackApp) createUI() {
 	fa.submitButton.Importance = widget.HighImportance
 
 	feedbackContainer := container.NewVBox(
-		promptLabel,
+		promptView,
 		fa.feedbackText,
 		fa.submitButton,
 	)
@@ -131,11 +147,41 @@ func (fa *FeedbackApp) createUI() {
 }
 
 func (fa *FeedbackApp) loadConfig() {
-	config := fa.configManager.LoadProjectConfig(fa.projectDirectory)
-	fa.commandEntry.SetText(config.RunCommand)
+	projectConfig := fa.configManager.LoadProjectConfig(fa.projectDirectory)
+	fa.projectConfig = projectConfig
+	if userConfig, err := fa.configManager.LoadUserConfig(); err == nil {
+		fa.commandLimits = userConfig.CommandLimits
+	}
 
-	if config.ExecuteAutomatically && config.RunCommand != "" {
-		fa.runCommand()
+	if len(projectConfig.Commands) > 0 {
+		fa.profileSelect.Options = config.CommandProfileNames(projectConfig)
+		fa.profileSelect.Show()
+	}
+	if profile, ok := config.DefaultCommandProfile(projectConfig); ok {
+		fa.selectProfile(profile.Name)
+	}
+
+	// Only one command runs at a time here; start the first auto-run profile
+	for _, profile := range config.CommandProfiles(projectConfig) {
+		if profile.AutoRun && profile.Command != "" {
+			fa.selectProfile(profile.Name)
+			fa.runCommand()
+			break
+		}
+	}
+}
+
+// selectProfile shows a named command in the command entry
+func (fa *FeedbackApp) selectProfile(name string) {
+	profile, ok := config.FindCommandProfile(fa.projectConfig, name)
+	if !ok {
+		return
+	}
+
+	fa.selectedProfile = profile
+	fa.commandEntry.SetText(profile.Command)
+	if fa.profileSelect.Selected != name {
+		fa.profileSelect.SetSelected(name)
 	}
 }
 
@@ -145,7 +191,7 @@ func (fa *FeedbackApp) runCommand() {
 		return
 	}
 
-	if fa.currentHandle != nil && fa.currentHandle.IsRunning {
+	if fa.currentHandle != nil && fa.currentHandle.IsRunning() {
 		// Stop current command
 		fa.commandExecutor.KillProcessTree(fa.currentHandle.PID)
 		fa.runButton.SetText("Run")
@@ -153,11 +199,40 @@ func (fa *FeedbackApp) runCommand() {
 		return
 	}
 
-	// Start new command
+	// New or changed commands need the user's approval first, including
+	// changes to their shell, working directory or environment
+	opts := executor.OptionsForProfile(fa.projectConfig, fa.selectedProfile)
+	spec := executor.LaunchSpec(command, fa.projectDirectory, opts)
+	approved, err := fa.configManager.IsCommandApproved(fa.projectDirectory, spec)
+	if err != nil {
+		dialog.ShowError(err, fa.window)
+		return
+	}
+	if !approved {
+		message := fmt.Sprintf("This project wants to run a new or changed command:\n\n%s\n\nRun it and remember this approval?", spec)
+		dialog.ShowConfirm("Run project command?", message, func(ok bool) {
+			if !ok {
+				return
+			}
+			if err := fa.configManager.ApproveCommand(fa.projectDirectory, spec); err != nil {
+				dialog.ShowError(err, fa.window)
+				return
+			}
+			fa.startCommand(command)
+		}, fa.window)
+		return
+	}
+
+	fa.startCommand(command)
+}
+
+// startCommand runs an approved command and streams its output to the console
+func (fa *FeedbackApp) startCommand(command string) {
 	fa.runButton.SetText("Stop")
 	fa.appendToConsole(fmt.Sprintf("$ %s\n", command))
 
-	handle, err := fa.commandExecutor.ExecuteCommand(command, fa.projectDirectory)
+	opts := executor.OptionsForProfile(fa.projectConfig, fa.selectedProfile).WithLimits(fa.commandLimits)
+	handle, err := fa.commandExecutor.ExecuteCommandWithOptions(command, fa.projectDirectory, opts)
 	if err != nil {
 		fa.appendToConsole(fmt.Sprintf("Error: %v\n", err))
 		fa.runButton.SetText("Run")
@@ -171,17 +246,43 @@ func (fa *FeedbackApp) runCommand() {
 		for output := range handle.Output {
 			fa.appendToConsole(output)
 		}
+		<-handle.Done
 
 		// Command finished
 		fa.runButton.SetText("Run")
 		fa.currentHandle = nil
+		fa.addCommandResult(fa.selectedProfile.Name, handle.Result)
 	}()
 }
 
+// historyOutputBytes bounds the command output kept in the conversation history
+const historyOutputBytes = 4096
+
+// addCommandResult records a finished command in the conversation
+func (fa *FeedbackApp) addCommandResult(profile string, result *types.CommandResult) {
+	output := executor.PlainText(result.Output)
+	if len(output) > historyOutputBytes {
+		output = "…" + strings.ToValidUTF8(output[len(output)-historyOutputBytes:], "")
+	}
+
+	exitCode := result.ExitCode
+	payload := &types.CommandResultPayload{
+		Name:       profile,
+		Command:    result.Command,
+		ExitCode:   &exitCode,
+		Signal:     result.Signal,
+		TimedOut:   result.TimedOut,
+		DurationMs: result.DurationMs,
+		Output:     output,
+	}
+	fa.conversationSection.AddPayloadEntry("user", conversation.Text(types.ConversationEntry{Payload: payload}), payload)
+}
+
 func (fa *FeedbackApp) appendToConsole(text string) {
-	currentText := fa.consoleText.Text
-	fa.consoleText.SetText(currentText + text)
-	fa.consoleText.CursorRow = len(strings.Split(fa.consoleText.Text, "\n")) - 1
+	fa.consoleLog.WriteString(text)
+	fa.consoleText.Segments = append(fa.consoleText.Segments, consoleSegments(text)...)
+	fa.consoleText.Refresh()
+	fa.consoleScroll.ScrollToBottom()
 }
 
 func (fa *FeedbackApp) copyToClipboard(text string) {
@@ -192,6 +293,36 @@ func (fa *FeedbackApp) copyToClipboard(text string) {
 	dialog.ShowInformation("Copied", "Conversation copied to clipboard!", fa.window)
 }
 
+func (fa *FeedbackApp) copyCodeToClipboard(code string) {
+	fa.window.Clipboard().SetContent(code)
+	dialog.ShowInformation("Copied", "Code copied to clipboard!", fa.window)
+}
+
+// saveConversation asks for a file and exports the thread to it in the
+// format its extension names (.md, .json or .txt)
+func (fa *FeedbackApp) saveConversation(thread export.Thread) {
+	saveDialog := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
+		if err != nil {
+			dialog.ShowError(err, fa.window)
+			return
+		}
+		if writer == nil {
+			return // cancelled
+		}
+		defer writer.Close()
+
+		text, err := export.Render(thread, export.FormatForPath(writer.URI().Path()))
+		if err == nil {
+			_, err = writer.Write([]byte(text))
+		}
+		if err != nil {
+			dialog.ShowError(fmt.Errorf("failed to save conversation: %w", err), fa.window)
+		}
+	}, fa.window)
+	saveDialog.SetFileName("conversation.md")
+	saveDialog.Show()
+}
+
 func (fa *FeedbackApp) clearConversationHistory() {
 	// Clear conversation history
 	fa.conversationSection.clearHistory()
@@ -207,18 +338,25 @@ func (fa *FeedbackApp) submitFeedback() {
 		fa.conversationSection.AddEntry("user", feedback)
 	}
 
-	// Save configuration
-	config := fa.configManager.LoadProjectConfig(fa.projectDirectory)
-	config.RunCommand = fa.commandEntry.Text
+	// Save configuration, keeping edits to the selected command
+	projectConfig := fa.configManager.LoadProjectConfig(fa.projectDirectory)
+	if len(projectConfig.Commands) == 0 {
+		projectConfig.RunCommand = fa.commandEntry.Text
+	}
+	for i := range projectConfig.Commands {
+		if projectConfig.Commands[i].Name == fa.selectedProfile.Name {
+			projectConfig.Commands[i].Command = fa.commandEntry.Text
+		}
+	}
 
-	if err := fa.configManager.SaveProjectConfig(fa.projectDirectory, config); err != nil {
+	if err := fa.configManager.SaveProjectConfig(fa.projectDirectory, projectConfig); err != nil {
 		dialog.ShowError(fmt.Errorf("failed to save config: %w", err), fa.window)
 		return
 	}
 
 	// Create response
 	response := &types.FeedbackResult{
-		CommandLogs:         fa.consoleText.Text,
+		CommandLogs:         executor.PlainText(fa.consoleLog.String()),
 		InteractiveFeedback: fa.feedbackText.Text,
 		ConversationHistory: fa.conversationSection.entries,
 	}
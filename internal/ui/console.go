@@ -0,0 +1,54 @@
+package ui
+
+import (
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"interactive-feedback-mcp/internal/executor"
+)
+
+// consoleSegments converts command output to rich text, keeping ANSI colors
+// that have a close theme equivalent
+func consoleSegments(text string) []widget.RichTextSegment {
+	// Redraws with carriage returns cannot be replayed in a text widget
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	text = strings.ReplaceAll(text, "\r", "\n")
+
+	var segments []widget.RichTextSegment
+	for _, segment := range executor.ParseANSI(text) {
+		segments = append(segments, &widget.TextSegment{
+			Text: segment.Text,
+			Style: widget.RichTextStyle{
+				Inline:    true,
+				ColorName: consoleColor(segment.Style.Foreground),
+				TextStyle: fyne.TextStyle{
+					Monospace: true,
+					Bold:      segment.Style.Bold,
+					Italic:    segment.Style.Italic,
+				},
+			},
+		})
+	}
+	return segments
+}
+
+// consoleColor maps an ANSI foreground color to a theme color
+func consoleColor(color string) fyne.ThemeColorName {
+	switch strings.TrimPrefix(color, "bright-") {
+	case "red":
+		return theme.ColorNameError
+	case "green":
+		return theme.ColorNameSuccess
+	case "yellow":
+		return theme.ColorNameWarning
+	case "blue", "cyan", "magenta":
+		return theme.ColorNamePrimary
+	case "black":
+		return theme.ColorNameDisabled
+	default:
+		return theme.ColorNameForeground
+	}
+}
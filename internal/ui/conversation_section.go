@@ -9,21 +9,29 @@ import (
 	"fyne.io/fyne/v2/container"
 	"fyne.io/fyne/v2/widget"
 	"github.com/google/uuid"
+	"interactive-feedback-mcp/internal/conversation"
+	"interactive-feedback-mcp/internal/export"
 	"interactive-feedback-mcp/internal/types"
 )
 
 type ConversationSection struct {
-	container   *fyne.Container
-	historyList *widget.List
-	copyButton  *widget.Button
-	clearButton *widget.Button
-	entries     []types.ConversationEntry
-	onCopy      func(string)
-	onClear     func()
+	container     *fyne.Container
+	historyBox    *fyne.Container
+	historyScroll *container.Scroll
+	copyButton    *widget.Button
+	saveButton    *widget.Button
+	clearButton   *widget.Button
+	project       string
+	entries       []types.ConversationEntry
+	onCopy        func(string)
+	onSave        func(export.Thread)
+	onCopyCode    func(string)
+	onClear       func()
 }
 
-func NewConversationSection() *ConversationSection {
+func NewConversationSection(project string) *ConversationSection {
 	cs := &ConversationSection{
+		project: project,
 		entries: make([]types.ConversationEntry, 0),
 	}
 
@@ -32,59 +40,21 @@ func NewConversationSection() *ConversationSection {
 }
 
 func (cs *ConversationSection) createUI() {
-	// Create conversation list
-	cs.historyList = widget.NewList(
-		func() int {
-			return len(cs.entries)
-		},
-		func() fyne.CanvasObject {
-			return container.NewVBox(
-				widget.NewLabel(""), // Role label
-				widget.NewLabel(""), // Content label
-				widget.NewLabel(""), // Timestamp label
-			)
-		},
-		func(id widget.ListItemID, obj fyne.CanvasObject) {
-			if id >= len(cs.entries) {
-				return
-			}
-
-			entry := cs.entries[id]
-			container := obj.(*fyne.Container)
-
-			// Role label with styling
-			roleLabel := container.Objects[0].(*widget.Label)
-			roleLabel.SetText(fmt.Sprintf("%s:", strings.Title(entry.Role)))
-
-			// Style based on role
-			if entry.Role == "user" {
-				roleLabel.Importance = widget.MediumImportance
-			} else {
-				roleLabel.Importance = widget.HighImportance
-			}
-
-			// Content label
-			contentLabel := container.Objects[1].(*widget.Label)
-			contentLabel.SetText(entry.Content)
-			contentLabel.Wrapping = fyne.TextWrapWord
-
-			// Timestamp label
-			timeLabel := container.Objects[2].(*widget.Label)
-			timeLabel.SetText(entry.Timestamp.Format("15:04:05"))
-			timeLabel.Importance = widget.LowImportance
-		},
-	)
-
-	// Set minimum height for conversation list
-	cs.historyList.Resize(fyne.NewSize(0, 200))
+	// Entries differ in height once rendered as Markdown, so they are
+	// stacked in a scroll container rather than a list
+	cs.historyBox = container.NewVBox()
+	cs.historyScroll = container.NewVScroll(cs.historyBox)
+	cs.historyScroll.SetMinSize(fyne.NewSize(0, 200))
 
 	// Create buttons
 	cs.copyButton = widget.NewButton("Copy All", cs.copyAllConversation)
+	cs.saveButton = widget.NewButton("Save…", cs.saveConversation)
 	cs.clearButton = widget.NewButton("Clear History", cs.clearHistory)
 
 	// Button container
 	buttonContainer := container.NewHBox(
 		cs.copyButton,
+		cs.saveButton,
 		widget.NewSeparator(),
 		cs.clearButton,
 	)
@@ -92,20 +62,72 @@ func (cs *ConversationSection) createUI() {
 	// Main container
 	cs.container = container.NewVBox(
 		widget.NewLabel("Conversation History"),
-		cs.historyList,
+		cs.historyScroll,
 		buttonContainer,
 	)
 }
 
+// entryView renders one entry: its role, its body as Markdown and its time
+func (cs *ConversationSection) entryView(entry types.ConversationEntry) fyne.CanvasObject {
+	// Role label with styling
+	roleLabel := widget.NewLabel("")
+	if entry.Kind != "" && entry.Kind != types.EntryKindText {
+		roleLabel.SetText(fmt.Sprintf("%s (%s):", strings.Title(entry.Role), strings.ReplaceAll(entry.Kind, "_", " ")))
+	} else {
+		roleLabel.SetText(fmt.Sprintf("%s:", strings.Title(entry.Role)))
+	}
+
+	// Style based on role
+	if entry.Role == "user" {
+		roleLabel.Importance = widget.MediumImportance
+	} else {
+		roleLabel.Importance = widget.HighImportance
+	}
+
+	// Timestamp label
+	timeLabel := widget.NewLabel(entry.Timestamp.Format("15:04:05"))
+	timeLabel.Importance = widget.LowImportance
+
+	return container.NewVBox(
+		roleLabel,
+		markdownView(conversation.Markdown(entry), cs.copyCode),
+		timeLabel,
+	)
+}
+
+// refreshHistory re-renders all entries
+func (cs *ConversationSection) refreshHistory() {
+	views := make([]fyne.CanvasObject, len(cs.entries))
+	for i, entry := range cs.entries {
+		views[i] = cs.entryView(entry)
+	}
+	cs.historyBox.Objects = views
+	cs.historyBox.Refresh()
+}
+
 func (cs *ConversationSection) AddEntry(role, content string) {
-	entry := types.ConversationEntry{
+	cs.addEntry(types.ConversationEntry{
 		ID:        uuid.New().String(),
 		Timestamp: time.Now(),
 		Role:      role,
 		Content:   content,
 		IsCurrent: false,
-	}
+	})
+}
+
+// AddPayloadEntry adds a structured entry; content is its text summary
+func (cs *ConversationSection) AddPayloadEntry(role, content string, payload types.EntryPayload) {
+	cs.addEntry(types.ConversationEntry{
+		ID:        uuid.New().String(),
+		Timestamp: time.Now(),
+		Role:      role,
+		Content:   content,
+		Kind:      payload.EntryKind(),
+		Payload:   payload,
+	})
+}
 
+func (cs *ConversationSection) addEntry(entry types.ConversationEntry) {
 	// Mark previous entries as not current
 	for i := range cs.entries {
 		cs.entries[i].IsCurrent = false
@@ -120,29 +142,38 @@ func (cs *ConversationSection) AddEntry(role, content string) {
 	}
 
 	// Refresh the list
-	cs.historyList.Refresh()
+	cs.refreshHistory()
 
 	// Scroll to bottom
-	cs.historyList.ScrollToBottom()
+	cs.historyScroll.ScrollToBottom()
+}
+
+// Thread returns the entries for export
+func (cs *ConversationSection) Thread() export.Thread {
+	return export.Thread{Project: cs.project, Entries: cs.entries}
 }
 
 func (cs *ConversationSection) copyAllConversation() {
-	var conversation strings.Builder
+	if cs.onCopy != nil {
+		cs.onCopy(export.Markdown(cs.Thread()))
+	}
+}
 
-	for _, entry := range cs.entries {
-		conversation.WriteString(fmt.Sprintf("%s: %s\n",
-			strings.Title(entry.Role),
-			entry.Content))
+func (cs *ConversationSection) saveConversation() {
+	if cs.onSave != nil {
+		cs.onSave(cs.Thread())
 	}
+}
 
-	if cs.onCopy != nil {
-		cs.onCopy(conversation.String())
+func (cs *ConversationSection) copyCode(code string) {
+	if cs.onCopyCode != nil {
+		cs.onCopyCode(code)
 	}
 }
 
 func (cs *ConversationSection) clearHistory() {
 	cs.entries = make([]types.ConversationEntry, 0)
-	cs.historyList.Refresh()
+	cs.refreshHistory()
 
 	if cs.onClear != nil {
 		cs.onClear()
@@ -157,6 +188,16 @@ func (cs *ConversationSection) SetOnCopy(callback func(string)) {
 	cs.onCopy = callback
 }
 
+// SetOnCopyCode sets what the copy button of a code block does
+func (cs *ConversationSection) SetOnCopyCode(callback func(string)) {
+	cs.onCopyCode = callback
+}
+
+// SetOnSave sets what the save button does with the thread
+func (cs *ConversationSection) SetOnSave(callback func(export.Thread)) {
+	cs.onSave = callback
+}
+
 func (cs *ConversationSection) SetOnClear(callback func()) {
 	cs.onClear = callback
 }
@@ -0,0 +1,82 @@
+package ui
+
+import (
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"interactive-feedback-mcp/internal/markdown"
+)
+
+// markdownView renders Markdown, showing fenced code blocks highlighted and
+// with a button that passes the block's code to onCopy
+func markdownView(text string, onCopy func(string)) fyne.CanvasObject {
+	view := container.NewVBox()
+	for _, block := range markdown.Split(text) {
+		if block.Code {
+			view.Add(codeBlockView(block, onCopy))
+			continue
+		}
+		richText := widget.NewRichTextFromMarkdown(block.Text)
+		richText.Wrapping = fyne.TextWrapWord
+		view.Add(richText)
+	}
+	return view
+}
+
+// codeBlockView shows one code block on the input background, scrolling
+// sideways rather than wrapping long lines
+func codeBlockView(block markdown.Block, onCopy func(string)) fyne.CanvasObject {
+	code := widget.NewRichText(codeSegments(block.Text, block.Language)...)
+
+	languageLabel := widget.NewLabel(block.Language)
+	languageLabel.Importance = widget.LowImportance
+	copyButton := widget.NewButtonWithIcon("", theme.ContentCopyIcon(), func() {
+		if onCopy != nil {
+			onCopy(block.Text)
+		}
+	})
+	copyButton.Importance = widget.LowImportance
+	header := container.NewBorder(nil, nil, languageLabel, copyButton)
+
+	background := canvas.NewRectangle(theme.Color(theme.ColorNameInputBackground))
+	background.CornerRadius = theme.InputRadiusSize()
+	return container.NewStack(background, container.NewBorder(header, nil, nil, nil, container.NewHScroll(code)))
+}
+
+// codeSegments converts highlighted code to rich text
+func codeSegments(code, language string) []widget.RichTextSegment {
+	var segments []widget.RichTextSegment
+	for _, token := range markdown.Highlight(code, language) {
+		segments = append(segments, &widget.TextSegment{
+			Text: token.Text,
+			Style: widget.RichTextStyle{
+				Inline:    true,
+				ColorName: tokenColor(token.Kind),
+				TextStyle: fyne.TextStyle{
+					Monospace: true,
+					Italic:    token.Kind == markdown.TokenComment,
+				},
+			},
+		})
+	}
+	return segments
+}
+
+// tokenColor maps a kind of code token to a theme color
+func tokenColor(kind markdown.TokenKind) fyne.ThemeColorName {
+	switch kind {
+	case markdown.TokenKeyword:
+		return theme.ColorNamePrimary
+	case markdown.TokenString:
+		return theme.ColorNameSuccess
+	case markdown.TokenNumber:
+		return theme.ColorNameWarning
+	case markdown.TokenComment:
+		return theme.ColorNamePlaceHolder
+	default:
+		return theme.ColorNameForeground
+	}
+}
@@ -0,0 +1,103 @@
+package workspace
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Resolve validates an agent-supplied project directory and returns its
+// canonical path. Relative paths are resolved against the workspace roots.
+// When allowed is not empty the canonical path must sit inside one of its
+// entries, so symlinks cannot point the server outside the workspace.
+func Resolve(projectDir string, roots []string, allowed []string) (string, error) {
+	projectDir = strings.TrimSpace(projectDir)
+	if projectDir == "" {
+		return "", fmt.Errorf("projectDirectory is required")
+	}
+
+	path := projectDir
+	var base string
+	if !filepath.IsAbs(path) {
+		var err error
+		path, base, err = resolveRelative(projectDir, roots)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	info, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		return "", fmt.Errorf("projectDirectory %s does not exist", path)
+	}
+	if err != nil {
+		return "", fmt.Errorf("cannot access projectDirectory %s: %w", path, err)
+	}
+	if !info.IsDir() {
+		return "", fmt.Errorf("projectDirectory %s is not a directory", path)
+	}
+
+	canonical, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return "", fmt.Errorf("cannot resolve projectDirectory %s: %w", path, err)
+	}
+	canonical = filepath.Clean(canonical)
+
+	// A relative path must stay inside the root it was resolved against
+	if base != "" {
+		canonicalBase, err := filepath.EvalSymlinks(base)
+		if err != nil {
+			return "", fmt.Errorf("cannot resolve workspace root %s: %w", base, err)
+		}
+		if !Contains(canonicalBase, canonical) {
+			return "", fmt.Errorf("projectDirectory %s escapes workspace root %s through a symlink", projectDir, base)
+		}
+	}
+
+	if len(allowed) > 0 && !Within(canonical, allowed) {
+		return "", fmt.Errorf("projectDirectory %s is outside the allowed workspace roots", canonical)
+	}
+
+	return canonical, nil
+}
+
+// Contains reports whether path is root itself or lies below it.
+// Both paths should already be canonical.
+func Contains(root, path string) bool {
+	rel, err := filepath.Rel(root, path)
+	if err != nil {
+		return false
+	}
+	return rel == "." || (rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)))
+}
+
+func resolveRelative(projectDir string, roots []string) (string, string, error) {
+	if len(roots) == 0 {
+		return "", "", fmt.Errorf("projectDirectory must be an absolute path, got %q", projectDir)
+	}
+
+	for _, root := range roots {
+		candidate := filepath.Join(root, projectDir)
+		if info, err := os.Stat(candidate); err == nil && info.IsDir() {
+			return candidate, root, nil
+		}
+	}
+
+	return "", "", fmt.Errorf("projectDirectory %q was not found in any workspace root", projectDir)
+}
+
+// Within reports whether a canonical path lies inside any of the roots.
+// Roots are canonicalized first; roots that cannot be resolved are skipped.
+func Within(path string, roots []string) bool {
+	for _, root := range roots {
+		canonicalRoot, err := filepath.EvalSymlinks(root)
+		if err != nil {
+			continue
+		}
+		if Contains(canonicalRoot, path) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,89 @@
+package workspace
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func canonicalTempDir(t *testing.T) string {
+	dir, err := filepath.EvalSymlinks(t.TempDir())
+	require.NoError(t, err)
+	return dir
+}
+
+func TestResolve_AbsolutePath(t *testing.T) {
+	dir := canonicalTempDir(t)
+
+	resolved, err := Resolve(dir, nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, dir, resolved)
+}
+
+func TestResolve_Invalid(t *testing.T) {
+	dir := canonicalTempDir(t)
+	file := filepath.Join(dir, "file.txt")
+	require.NoError(t, os.WriteFile(file, []byte("x"), 0644))
+
+	_, err := Resolve("", nil, nil)
+	assert.Error(t, err)
+
+	_, err = Resolve(".", nil, nil)
+	assert.ErrorContains(t, err, "absolute")
+
+	_, err = Resolve(filepath.Join(dir, "missing"), nil, nil)
+	assert.ErrorContains(t, err, "does not exist")
+
+	_, err = Resolve(file, nil, nil)
+	assert.ErrorContains(t, err, "not a directory")
+}
+
+func TestResolve_RelativeToRoots(t *testing.T) {
+	root := canonicalTempDir(t)
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "app"), 0755))
+
+	resolved, err := Resolve("app", []string{root}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(root, "app"), resolved)
+
+	_, err = Resolve("missing", []string{root}, nil)
+	assert.Error(t, err)
+}
+
+func TestResolve_AllowList(t *testing.T) {
+	allowed := canonicalTempDir(t)
+	outside := canonicalTempDir(t)
+	require.NoError(t, os.MkdirAll(filepath.Join(allowed, "app"), 0755))
+
+	_, err := Resolve(filepath.Join(allowed, "app"), nil, []string{allowed})
+	assert.NoError(t, err)
+
+	_, err = Resolve(outside, nil, []string{allowed})
+	assert.ErrorContains(t, err, "outside the allowed workspace roots")
+}
+
+func TestResolve_SymlinkEscape(t *testing.T) {
+	root := canonicalTempDir(t)
+	outside := canonicalTempDir(t)
+	link := filepath.Join(root, "escape")
+	if err := os.Symlink(outside, link); err != nil {
+		t.Skipf("symlinks not supported: %v", err)
+	}
+
+	_, err := Resolve("escape", []string{root}, nil)
+	assert.ErrorContains(t, err, "symlink")
+
+	_, err = Resolve(link, nil, []string{root})
+	assert.ErrorContains(t, err, "outside the allowed workspace roots")
+}
+
+func TestContains(t *testing.T) {
+	root := filepath.FromSlash("/work/project")
+	assert.True(t, Contains(root, root))
+	assert.True(t, Contains(root, filepath.Join(root, "sub")))
+	assert.False(t, Contains(root, filepath.FromSlash("/work/project-other")))
+	assert.False(t, Contains(root, filepath.FromSlash("/work")))
+}
@@ -21,19 +21,19 @@ echo "📦 Building binaries for all platforms..."
 
 # Linux AMD64
 echo "Building for Linux AMD64..."
-GOOS=linux GOARCH=amd64 go build -ldflags="-s -w" -o ${BUILD_DIR}/interactive-feedback-mcp-linux-amd64 cmd/mcp-server-single/main.go
+GOOS=linux GOARCH=amd64 go build -ldflags="-s -w" -o ${BUILD_DIR}/interactive-feedback-mcp-linux-amd64 ./cmd/mcp-server-single
 
 # Windows AMD64
 echo "Building for Windows AMD64..."
-GOOS=windows GOARCH=amd64 go build -ldflags="-s -w" -o ${BUILD_DIR}/interactive-feedback-mcp-windows-amd64.exe cmd/mcp-server-single/main.go
+GOOS=windows GOARCH=amd64 go build -ldflags="-s -w" -o ${BUILD_DIR}/interactive-feedback-mcp-windows-amd64.exe ./cmd/mcp-server-single
 
 # macOS AMD64
 echo "Building for macOS AMD64..."
-GOOS=darwin GOARCH=amd64 go build -ldflags="-s -w" -o ${BUILD_DIR}/interactive-feedback-mcp-darwin-amd64 cmd/mcp-server-single/main.go
+GOOS=darwin GOARCH=amd64 go build -ldflags="-s -w" -o ${BUILD_DIR}/interactive-feedback-mcp-darwin-amd64 ./cmd/mcp-server-single
 
 # macOS ARM64 (Apple Silicon)
 echo "Building for macOS ARM64..."
-GOOS=darwin GOARCH=arm64 go build -ldflags="-s -w" -o ${BUILD_DIR}/interactive-feedback-mcp-darwin-arm64 cmd/mcp-server-single/main.go
+GOOS=darwin GOARCH=arm64 go build -ldflags="-s -w" -o ${BUILD_DIR}/interactive-feedback-mcp-darwin-arm64 ./cmd/mcp-server-single
 
 echo "📦 Creating packages..."
 
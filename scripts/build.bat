@@ -14,17 +14,17 @@ if not exist build mkdir build
 echo Building for Windows (amd64)...
 set GOOS=windows
 set GOARCH=amd64
-go build -ldflags="%LDFLAGS%" -o build/mcp-server-single-windows-amd64.exe cmd/mcp-server-single/main.go
+go build -ldflags="%LDFLAGS%" -o build/mcp-server-single-windows-amd64.exe ./cmd/mcp-server-single
 
 echo Building for Linux (amd64)...
 set GOOS=linux
 set GOARCH=amd64
-go build -ldflags="%LDFLAGS%" -o build/mcp-server-single-linux-amd64 cmd/mcp-server-single/main.go
+go build -ldflags="%LDFLAGS%" -o build/mcp-server-single-linux-amd64 ./cmd/mcp-server-single
 
 echo Building for macOS (amd64)...
 set GOOS=darwin
 set GOARCH=amd64
-go build -ldflags="%LDFLAGS%" -o build/mcp-server-single-macos-amd64 cmd/mcp-server-single/main.go
+go build -ldflags="%LDFLAGS%" -o build/mcp-server-single-macos-amd64 ./cmd/mcp-server-single
 
 echo Build completed successfully!
 echo Binaries are available in the build/ directory
\ No newline at end of file
@@ -19,19 +19,19 @@ echo "📦 Building for multiple platforms..."
 
 # Linux AMD64
 echo "Building for Linux AMD64..."
-GOOS=linux GOARCH=amd64 go build -ldflags="-s -w" -o ${BUILD_DIR}/mcp-server-single-linux-amd64 cmd/mcp-server-single/main.go
+GOOS=linux GOARCH=amd64 go build -ldflags="-s -w" -o ${BUILD_DIR}/mcp-server-single-linux-amd64 ./cmd/mcp-server-single
 
 # Windows AMD64
 echo "Building for Windows AMD64..."
-GOOS=windows GOARCH=amd64 go build -ldflags="-s -w" -o ${BUILD_DIR}/mcp-server-single-windows-amd64.exe cmd/mcp-server-single/main.go
+GOOS=windows GOARCH=amd64 go build -ldflags="-s -w" -o ${BUILD_DIR}/mcp-server-single-windows-amd64.exe ./cmd/mcp-server-single
 
 # macOS AMD64
 echo "Building for macOS AMD64..."
-GOOS=darwin GOARCH=amd64 go build -ldflags="-s -w" -o ${BUILD_DIR}/mcp-server-single-darwin-amd64 cmd/mcp-server-single/main.go
+GOOS=darwin GOARCH=amd64 go build -ldflags="-s -w" -o ${BUILD_DIR}/mcp-server-single-darwin-amd64 ./cmd/mcp-server-single
 
 # macOS ARM64 (Apple Silicon)
 echo "Building for macOS ARM64..."
-GOOS=darwin GOARCH=arm64 go build -ldflags="-s -w" -o ${BUILD_DIR}/mcp-server-single-darwin-arm64 cmd/mcp-server-single/main.go
+GOOS=darwin GOARCH=arm64 go build -ldflags="-s -w" -o ${BUILD_DIR}/mcp-server-single-darwin-arm64 ./cmd/mcp-server-single
 
 echo "✅ Build completed successfully!"
 echo "📁 Build artifacts are in the ${BUILD_DIR}/ directory"